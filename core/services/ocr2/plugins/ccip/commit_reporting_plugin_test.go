@@ -3,6 +3,7 @@ package ccip
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/big"
 	"math/rand"
 	"reflect"
@@ -10,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -21,11 +23,13 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/smartcontractkit/libocr/commontypes"
 	"github.com/smartcontractkit/libocr/offchainreporting2plus/types"
 
 	"github.com/smartcontractkit/chainlink/v2/core/assets"
 	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
 	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	mocklp "github.com/smartcontractkit/chainlink/v2/core/chains/evm/logpoller/mocks"
 	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/commit_store"
 	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_onramp"
 	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/price_registry"
@@ -35,6 +39,7 @@ import (
 	ccipconfig "github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/config"
 	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/cache"
 	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/ccipdata"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/contractutil"
 	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/hashlib"
 	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/merklemulti"
 	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/pricegetter"
@@ -1058,6 +1063,681 @@ func TestCommitReportingPlugin_calculateMinMaxSequenceNumbers(t *testing.T) {
 	}
 }
 
+func TestCommitReportingPlugin_restart(t *testing.T) {
+	ctx := testutils.Context(t)
+	lggr := logger.TestLogger(t)
+	onChainSeqNum := uint64(54)
+
+	commitStore, _ := testhelpers.NewFakeCommitStore(t, onChainSeqNum)
+	offRamp, _ := testhelpers.NewFakeOffRamp(t)
+	sourceReader := ccipdata.NewMockReader(t)
+	sourceReader.On("GetSendRequestsGteSeqNum", ctx, mock.Anything, onChainSeqNum, false, 1).
+		Return([]ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested]{
+			{Data: evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested{Message: evm_2_evm_onramp.InternalEVM2EVMMessage{SequenceNumber: 54}}},
+			{Data: evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested{Message: evm_2_evm_onramp.InternalEVM2EVMMessage{SequenceNumber: 55}}},
+		}, nil).Once()
+	sourceLP := mocklp.NewLogPoller(t)
+	sourceLP.On("RegisterFilter", mock.Anything).Return(nil)
+	destLP := mocklp.NewLogPoller(t)
+	destLP.On("RegisterFilter", mock.Anything).Return(nil)
+	pluginConfig := CommitPluginConfig{
+		lggr:          lggr,
+		sourceLP:      sourceLP,
+		destLP:        destLP,
+		sourceReader:  sourceReader,
+		offRamp:       offRamp,
+		onRampAddress: utils.RandomAddress(),
+		commitStore:   commitStore,
+	}
+	onchainConfig, err := abihelpers.EncodeAbiStruct(ccipconfig.CommitOnchainConfig{PriceRegistry: utils.RandomAddress()})
+	require.NoError(t, err)
+	offchainConfig, err := ccipconfig.EncodeOffchainConfig(ccipconfig.CommitOffchainConfig{
+		SourceFinalityDepth:   1,
+		DestFinalityDepth:     1,
+		FeeUpdateHeartBeat:    models.MustMakeDuration(time.Hour),
+		FeeUpdateDeviationPPB: 1,
+		MaxGasPrice:           200e9,
+		InflightCacheExpiry:   models.MustMakeDuration(time.Hour),
+	})
+	require.NoError(t, err)
+	// A restarted node builds its plugin from the same config as before, through a new factory.
+	newPlugin := func() *CommitReportingPlugin {
+		plugin, _, err2 := NewCommitReportingPluginFactory(pluginConfig).NewReportingPlugin(types.ReportingPluginConfig{
+			F:              1,
+			OnchainConfig:  onchainConfig,
+			OffchainConfig: offchainConfig,
+		})
+		require.NoError(t, err2)
+		return plugin.(*CommitReportingPlugin)
+	}
+	p := newPlugin()
+
+	// The round starts before the restart, the report is accepted but not yet transmitted.
+	minSeqNum, maxSeqNum, err := p.calculateMinMaxSequenceNumbers(ctx, lggr)
+	require.NoError(t, err)
+	report := commit_store.CommitStoreCommitReport{
+		PriceUpdates: commit_store.InternalPriceUpdates{UsdPerUnitGas: big.NewInt(0)},
+		MerkleRoot:   [32]byte{123},
+		Interval:     commit_store.CommitStoreInterval{Min: minSeqNum, Max: maxSeqNum},
+	}
+	encodedReport, err := abihelpers.EncodeCommitReport(report)
+	require.NoError(t, err)
+	shouldAccept, err := p.ShouldAcceptFinalizedReport(ctx, types.ReportTimestamp{}, encodedReport)
+	require.NoError(t, err)
+	require.True(t, shouldAccept)
+	require.Equal(t, maxSeqNum, p.inflightReports.maxInflightSeqNr())
+
+	restarted := newPlugin()
+	assert.Equal(t, p.onchainConfig, restarted.onchainConfig)
+	assert.Equal(t, p.offchainConfig, restarted.offchainConfig)
+	assert.Zero(t, restarted.inflightReports.maxInflightSeqNr())
+	assertRoundCompletesAfterRestart(t, restarted, commitStore, report)
+}
+
+func TestCommitReportingPlugin_flakyRPC(t *testing.T) {
+	ctx := testutils.Context(t)
+	lggr := logger.TestLogger(t)
+	c := testhelpers.SetupCCIPContracts(t, testhelpers.SourceChainID, testhelpers.SourceChainSelector, testhelpers.DestChainID, testhelpers.DestChainSelector)
+	backend := testhelpers.NewFaultInjectingBackend(c.Dest.Chain, 1)
+	commitStore, err := commit_store.NewCommitStore(c.Dest.CommitStore.Address(), backend)
+	require.NoError(t, err)
+
+	// Every round finds two new messages starting at the sequence number it asks for.
+	sourceReader := ccipdata.NewMockReader(t)
+	sourceReader.On("GetSendRequestsGteSeqNum", ctx, mock.Anything, mock.Anything, false, 0).
+		Return(func(_ context.Context, _ common.Address, seqNum uint64, _ bool, _ int) ([]ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested], error) {
+			return []ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested]{
+				{Data: evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested{Message: evm_2_evm_onramp.InternalEVM2EVMMessage{SequenceNumber: seqNum}}},
+				{Data: evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested{Message: evm_2_evm_onramp.InternalEVM2EVMMessage{SequenceNumber: seqNum + 1}}},
+			}, nil
+		})
+
+	p := &CommitReportingPlugin{}
+	p.lggr = lggr
+	p.inflightReports = newInflightCommitReportsContainer(time.Hour)
+	p.config.commitStore = commitStore
+	p.config.sourceReader = sourceReader
+	backend.SetFailureRate(0.2)
+	backend.SetLatency(time.Millisecond)
+
+	// Every step of a round is retried, as the next OCR round would, until it gets through without faults.
+	// Steps hit by a fault must fail closed, without observing anything or accepting or transmitting the report.
+	nextSeqNum := uint64(1)
+	for round := 1; round <= 10; round++ {
+		var interval commit_store.CommitStoreInterval
+		require.True(t, retryOnFault(t, backend, func() bool {
+			if contractutil.IsCommitStoreDownNow(ctx, lggr, p.config.commitStore) {
+				return false
+			}
+			min, max, err2 := p.calculateMinMaxSequenceNumbers(ctx, lggr)
+			interval = commit_store.CommitStoreInterval{Min: min, Max: max}
+			return err2 == nil
+		}), "round %d is not observed", round)
+		require.Equal(t, commit_store.CommitStoreInterval{Min: nextSeqNum, Max: nextSeqNum + 1}, interval)
+
+		encodedReport, err := abihelpers.EncodeCommitReport(commit_store.CommitStoreCommitReport{
+			PriceUpdates: commit_store.InternalPriceUpdates{UsdPerUnitGas: big.NewInt(0)},
+			MerkleRoot:   [32]byte{byte(round)},
+			Interval:     interval,
+		})
+		require.NoError(t, err)
+		require.True(t, retryOnFault(t, backend, func() bool {
+			shouldAccept, err2 := p.ShouldAcceptFinalizedReport(ctx, types.ReportTimestamp{}, encodedReport)
+			require.NoError(t, err2)
+			return shouldAccept
+		}), "report of round %d is not accepted", round)
+		require.True(t, retryOnFault(t, backend, func() bool {
+			shouldTransmit, err2 := p.ShouldTransmitAcceptedReport(ctx, types.ReportTimestamp{}, encodedReport)
+			require.NoError(t, err2)
+			return shouldTransmit
+		}), "report of round %d is not transmitted", round)
+
+		// The report is transmitted through the unfaulted chain, standing in for the transmitter.
+		tx, err := c.Dest.CommitStoreHelper.Report(c.Dest.User, encodedReport, big.NewInt(int64(round)))
+		require.NoError(t, err)
+		testhelpers.ConfirmTxs(t, []*gethtypes.Transaction{tx}, c.Dest.Chain)
+		nextSeqNum = interval.Max + 1
+	}
+	assert.Positive(t, backend.Faults(), "no faults were injected")
+	onChainSeqNum, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	assert.Equal(t, nextSeqNum, onChainSeqNum)
+}
+
+func TestCommitReportingPlugin_priceDeviationThreshold(t *testing.T) {
+	c := testhelpers.SetupCCIPContracts(t, testhelpers.SourceChainID, testhelpers.SourceChainSelector, testhelpers.DestChainID, testhelpers.DestChainSelector)
+	c.SetupSimulatedOracles(t)
+	c.SetPriceDeviationThreshold(t, 100)
+
+	p := &CommitReportingPlugin{}
+	p.lggr = logger.TestLogger(t)
+	p.F = 1
+	p.offchainConfig = c.CommitOffchainConfig(t)
+	require.Equal(t, uint32(1e7), p.offchainConfig.FeeUpdateDeviationPPB)
+
+	token := common.HexToAddress("0xa")
+	val1e16 := func(val int64) *big.Int { return new(big.Int).Mul(big.NewInt(1e16), big.NewInt(val)) }
+	// Deviations are relative to the new price, so 100 moves 1% away from 99.
+	latestTokenPrices := map[common.Address]update{token: {timestamp: time.Now(), value: val1e16(9900)}}
+	for _, tc := range []struct {
+		name      string
+		price     *big.Int
+		expUpdate bool
+	}{
+		{name: "below threshold", price: val1e16(9999)},
+		{name: "at threshold", price: val1e16(10000)},
+		{name: "above threshold", price: val1e16(10001), expUpdate: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			observations := make([]CommitObservation, 3)
+			for i := range observations {
+				observations[i] = CommitObservation{TokenPricesUSD: map[common.Address]*big.Int{token: tc.price}}
+			}
+			priceUpdates := p.calculatePriceUpdates(observations, update{}, latestTokenPrices)
+			if !tc.expUpdate {
+				assert.Empty(t, priceUpdates.TokenPriceUpdates)
+				return
+			}
+			assert.Equal(t, []commit_store.InternalTokenPriceUpdate{{SourceToken: token, UsdPerToken: tc.price}}, priceUpdates.TokenPriceUpdates)
+		})
+	}
+}
+
+func TestCommitReportingPlugin_backlogExceedingCommitCap(t *testing.T) {
+	ctx := testutils.Context(t)
+	c := testhelpers.SetupCCIPContracts(t, testhelpers.SourceChainID, testhelpers.SourceChainSelector, testhelpers.DestChainID, testhelpers.DestChainSelector)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	seqNums := c.GenerateBacklogExceedingCommitCap(t, c.Source.User, merklemulti.MaxNumberTreeLeaves)
+	require.Greater(t, len(seqNums), merklemulti.MaxNumberTreeLeaves)
+
+	it, err := c.Source.OnRamp.FilterCCIPSendRequested(&bind.FilterOpts{Start: startBlock})
+	require.NoError(t, err)
+	var sendRequests []ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested]
+	for it.Next() {
+		sendRequests = append(sendRequests, ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested]{Data: *it.Event})
+	}
+	require.NoError(t, it.Error())
+	require.NoError(t, it.Close())
+	between := func(min, max uint64) []ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested] {
+		var reqs []ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested]
+		for _, req := range sendRequests {
+			if seqNum := req.Data.Message.SequenceNumber; seqNum >= min && seqNum <= max {
+				reqs = append(reqs, req)
+			}
+		}
+		return reqs
+	}
+
+	onRampAddress := c.Source.OnRamp.Address()
+	sourceReader := ccipdata.NewMockReader(t)
+	sourceReader.On("GetSendRequestsGteSeqNum", ctx, onRampAddress, mock.Anything, false, 0).
+		Return(func(_ context.Context, _ common.Address, seqNum uint64, _ bool, _ int) ([]ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested], error) {
+			return between(seqNum, math.MaxUint64), nil
+		})
+	sourceReader.On("GetSendRequestsBetweenSeqNums", ctx, onRampAddress, mock.Anything, mock.Anything, 0).
+		Return(func(_ context.Context, _ common.Address, min, max uint64, _ int) ([]ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested], error) {
+			return between(min, max), nil
+		})
+	destPriceRegistry, destPriceRegistryAddress := testhelpers.NewFakePriceRegistry(t)
+	destReader := ccipdata.NewMockReader(t)
+	destReader.On("GetGasPriceUpdatesCreatedAfter", ctx, destPriceRegistryAddress, c.Source.ChainSelector, mock.Anything, 0).Return(nil, nil)
+	destReader.On("GetTokenPriceUpdatesCreatedAfter", ctx, destPriceRegistryAddress, mock.Anything, 0).Return(nil, nil)
+
+	p := &CommitReportingPlugin{}
+	p.lggr = logger.TestLogger(t)
+	p.F = 1
+	p.inflightReports = newInflightCommitReportsContainer(time.Hour)
+	p.destPriceRegistry = destPriceRegistry
+	p.config.commitStore = c.Dest.CommitStore
+	p.config.destReader = destReader
+	p.config.sourceReader = sourceReader
+	p.config.onRampAddress = onRampAddress
+	p.config.sourceChainSelector = c.Source.ChainSelector
+	p.config.leafHasher = hashlib.NewLeafHasher(c.Source.ChainSelector, c.Dest.ChainSelector, onRampAddress, hashlib.NewKeccakCtx())
+
+	// Every round, all oracles observe the whole remaining backlog, which the report can only commit up to the cap of.
+	// The round after the last root observes nothing.
+	maxRounds := (len(seqNums)+merklemulti.MaxNumberTreeLeaves-1)/merklemulti.MaxNumberTreeLeaves + 1
+	for round := 1; ; round++ {
+		require.LessOrEqual(t, round, maxRounds, "backlog is not committed after %d rounds", round-1)
+		min, max, err := p.calculateMinMaxSequenceNumbers(ctx, p.lggr)
+		require.NoError(t, err)
+		if min == 0 {
+			break
+		}
+		require.Equal(t, seqNums[len(seqNums)-1], max)
+		obs, err := CommitObservation{Interval: commit_store.CommitStoreInterval{Min: min, Max: max}}.Marshal()
+		require.NoError(t, err)
+		aos := []types.AttributedObservation{{Observation: obs}, {Observation: obs}, {Observation: obs}}
+		shouldReport, report, err := p.Report(ctx, types.ReportTimestamp{}, types.Query{}, aos)
+		require.NoError(t, err)
+		require.True(t, shouldReport)
+
+		// The report is transmitted through the commitStore helper, standing in for the transmitter.
+		tx, err := c.Dest.CommitStoreHelper.Report(c.Dest.User, report, big.NewInt(int64(round)))
+		require.NoError(t, err)
+		testhelpers.ConfirmTxs(t, []*gethtypes.Transaction{tx}, c.Dest.Chain)
+	}
+	c.AssertMultipleRootsCommitted(t, seqNums, merklemulti.MaxNumberTreeLeaves)
+}
+
+func TestCommitReportingPlugin_onlyFinalizedMessagesCommitted(t *testing.T) {
+	const finalityDepth = 4
+	testCases := []struct {
+		name              string
+		checkFinalityTags bool
+	}{
+		{name: "finality depth"},
+		{name: "finality tags", checkFinalityTags: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := testutils.Context(t)
+			c := testhelpers.SetupCCIPContracts(t, testhelpers.SourceChainID, testhelpers.SourceChainSelector, testhelpers.DestChainID, testhelpers.DestChainSelector)
+			finalityTags := testhelpers.NewFinalityTagBackend(c.Source.Chain, finalityDepth)
+			onRampAddress := c.Source.OnRamp.Address()
+
+			// The source reader mimics the log poller, which only returns logs of finalized blocks: those at or
+			// behind the finalized block when finality tags are checked, and those confs blocks deep otherwise.
+			sendRequests := func(min, max uint64, checkFinalityTags bool, confs int) []ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested] {
+				finalized := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() - uint64(confs)
+				if checkFinalityTags {
+					finalized = finalityTags.FinalizedBlockNumber().Uint64()
+				}
+				it, err := c.Source.OnRamp.FilterCCIPSendRequested(&bind.FilterOpts{End: &finalized})
+				require.NoError(t, err)
+				defer it.Close()
+				var reqs []ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested]
+				for it.Next() {
+					if seqNum := it.Event.Message.SequenceNumber; seqNum >= min && seqNum <= max {
+						reqs = append(reqs, ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested]{
+							Data:      *it.Event,
+							BlockMeta: ccipdata.BlockMeta{BlockNumber: int64(it.Event.Raw.BlockNumber)},
+						})
+					}
+				}
+				require.NoError(t, it.Error())
+				return reqs
+			}
+			sourceReader := ccipdata.NewMockReader(t)
+			sourceReader.On("GetSendRequestsGteSeqNum", ctx, onRampAddress, mock.Anything, tc.checkFinalityTags, mock.Anything).
+				Return(func(_ context.Context, _ common.Address, seqNum uint64, checkFinalityTags bool, confs int) ([]ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested], error) {
+					return sendRequests(seqNum, math.MaxUint64, checkFinalityTags, confs), nil
+				})
+			sourceReader.On("GetSendRequestsBetweenSeqNums", ctx, onRampAddress, mock.Anything, mock.Anything, mock.Anything).
+				Return(func(_ context.Context, _ common.Address, min, max uint64, confs int) ([]ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested], error) {
+					return sendRequests(min, max, false, confs), nil
+				})
+			destPriceRegistry, destPriceRegistryAddress := testhelpers.NewFakePriceRegistry(t)
+			destReader := ccipdata.NewMockReader(t)
+			destReader.On("GetGasPriceUpdatesCreatedAfter", ctx, destPriceRegistryAddress, c.Source.ChainSelector, mock.Anything, 0).Return(nil, nil)
+			destReader.On("GetTokenPriceUpdatesCreatedAfter", ctx, destPriceRegistryAddress, mock.Anything, 0).Return(nil, nil)
+
+			p := &CommitReportingPlugin{}
+			p.lggr = logger.TestLogger(t)
+			p.F = 1
+			p.inflightReports = newInflightCommitReportsContainer(time.Hour)
+			p.destPriceRegistry = destPriceRegistry
+			p.config.commitStore = c.Dest.CommitStore
+			p.config.destReader = destReader
+			p.config.sourceReader = sourceReader
+			p.config.onRampAddress = onRampAddress
+			p.config.sourceChainSelector = c.Source.ChainSelector
+			p.config.leafHasher = hashlib.NewLeafHasher(c.Source.ChainSelector, c.Dest.ChainSelector, onRampAddress, hashlib.NewKeccakCtx())
+			p.config.checkFinalityTags = tc.checkFinalityTags
+			// With finality tags the depth is left to the tags, so a configured depth must not be relied on.
+			p.offchainConfig.SourceFinalityDepth = finalityDepth
+			if tc.checkFinalityTags {
+				p.offchainConfig.SourceFinalityDepth = 1
+			}
+
+			var round int64
+			c.AssertOnlyFinalizedMessagesCommitted(t, finalityDepth, func(t *testing.T) {
+				min, max, err := p.calculateMinMaxSequenceNumbers(ctx, p.lggr)
+				require.NoError(t, err)
+				if min == 0 {
+					return
+				}
+				obs, err := CommitObservation{Interval: commit_store.CommitStoreInterval{Min: min, Max: max}}.Marshal()
+				require.NoError(t, err)
+				aos := []types.AttributedObservation{{Observation: obs}, {Observation: obs}, {Observation: obs}}
+				shouldReport, report, err := p.Report(ctx, types.ReportTimestamp{}, types.Query{}, aos)
+				require.NoError(t, err)
+				require.True(t, shouldReport)
+
+				round++
+				tx, err := c.Dest.CommitStoreHelper.Report(c.Dest.User, report, big.NewInt(round))
+				require.NoError(t, err)
+				testhelpers.ConfirmTxs(t, []*gethtypes.Transaction{tx}, c.Dest.Chain)
+			})
+		})
+	}
+}
+
+func TestCommitReportingPlugin_sequenceGapNotCommitted(t *testing.T) {
+	ctx := testutils.Context(t)
+	c := testhelpers.SetupCCIPContracts(t, testhelpers.SourceChainID, testhelpers.SourceChainSelector, testhelpers.DestChainID, testhelpers.DestChainSelector)
+	onRampAddress := c.Source.OnRamp.Address()
+	const skipSeq, lastSeq = 4, 6
+
+	sendRequests := testhelpers.SimulateSequenceGap(t, c.Source.OnRamp, skipSeq)
+	sourceReader := ccipdata.NewMockReader(t)
+	sourceReader.On("GetSendRequestsGteSeqNum", ctx, onRampAddress, mock.Anything, false, 0).
+		Return(func(_ context.Context, _ common.Address, seqNum uint64, _ bool, _ int) ([]ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested], error) {
+			return sendRequests(seqNum, math.MaxUint64), nil
+		})
+	sourceReader.On("GetSendRequestsBetweenSeqNums", ctx, onRampAddress, mock.Anything, mock.Anything, 0).
+		Return(func(_ context.Context, _ common.Address, min, max uint64, _ int) ([]ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested], error) {
+			return sendRequests(min, max), nil
+		}).Maybe()
+	destPriceRegistry, destPriceRegistryAddress := testhelpers.NewFakePriceRegistry(t)
+	destReader := ccipdata.NewMockReader(t)
+	destReader.On("GetGasPriceUpdatesCreatedAfter", ctx, destPriceRegistryAddress, c.Source.ChainSelector, mock.Anything, 0).Return(nil, nil).Maybe()
+	destReader.On("GetTokenPriceUpdatesCreatedAfter", ctx, destPriceRegistryAddress, mock.Anything, 0).Return(nil, nil).Maybe()
+
+	p := &CommitReportingPlugin{}
+	p.lggr = logger.TestLogger(t)
+	p.F = 1
+	p.inflightReports = newInflightCommitReportsContainer(time.Hour)
+	p.destPriceRegistry = destPriceRegistry
+	p.config.commitStore = c.Dest.CommitStore
+	p.config.destReader = destReader
+	p.config.sourceReader = sourceReader
+	p.config.onRampAddress = onRampAddress
+	p.config.sourceChainSelector = c.Source.ChainSelector
+	p.config.leafHasher = hashlib.NewLeafHasher(c.Source.ChainSelector, c.Dest.ChainSelector, onRampAddress, hashlib.NewKeccakCtx())
+
+	// commitRound runs a commit round, committing the report of the plugin unless it is rejected, and returns whether
+	// a root was committed.
+	var round uint8
+	commitRound := func(t *testing.T) bool {
+		min, max, err := p.calculateMinMaxSequenceNumbers(ctx, p.lggr)
+		require.NoError(t, err)
+		if min == 0 {
+			return false
+		}
+		obs, err := CommitObservation{Interval: commit_store.CommitStoreInterval{Min: min, Max: max}}.Marshal()
+		require.NoError(t, err)
+		aos := []types.AttributedObservation{{Observation: obs}, {Observation: obs}, {Observation: obs}}
+		shouldReport, report, err := p.Report(ctx, types.ReportTimestamp{}, types.Query{}, aos)
+		require.NoError(t, err)
+		require.True(t, shouldReport)
+
+		round++
+		timestamp := types.ReportTimestamp{Epoch: 1, Round: round}
+		if accept, err := p.ShouldAcceptFinalizedReport(ctx, timestamp, report); err != nil || !accept {
+			require.NoError(t, err)
+			return false
+		}
+		transmit, err := p.ShouldTransmitAcceptedReport(ctx, timestamp, report)
+		require.NoError(t, err)
+		require.True(t, transmit)
+		tx, err := c.Dest.CommitStoreHelper.Report(c.Dest.User, report, big.NewInt(int64(round)))
+		require.NoError(t, err)
+		testhelpers.ConfirmTxs(t, []*gethtypes.Transaction{tx}, c.Dest.Chain)
+		return true
+	}
+
+	// The contiguous prefix is committed.
+	c.SendDataMessages(t, skipSeq-1, big.NewInt(100_000))
+	require.True(t, commitRound(t))
+
+	// Past the gap, the plugin observes the messages after it, but rejects a root that would not start at the gap.
+	c.SendDataMessages(t, lastSeq-skipSeq+1, big.NewInt(100_000))
+	require.False(t, commitRound(t))
+	c.AssertCommittedUpToGap(t, skipSeq, lastSeq)
+}
+
+func TestCommitReportingPlugin_singleTransmission(t *testing.T) {
+	ctx := testutils.Context(t)
+	c := testhelpers.SetupCCIPContracts(t, testhelpers.SourceChainID, testhelpers.SourceChainSelector, testhelpers.DestChainID, testhelpers.DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	plugins := make([]*CommitReportingPlugin, len(oracles))
+	for i := range oracles {
+		plugins[i] = newLaneCommitReportingPlugin(t, c)
+	}
+
+	for round := uint8(1); round <= 3; round++ {
+		c.SendDataMessages(t, 1, big.NewInt(100_000))
+		aos := make([]types.AttributedObservation, len(plugins))
+		for i, p := range plugins {
+			min, max, err := p.calculateMinMaxSequenceNumbers(ctx, p.lggr)
+			require.NoError(t, err)
+			obs, err := CommitObservation{Interval: commit_store.CommitStoreInterval{Min: min, Max: max}}.Marshal()
+			require.NoError(t, err)
+			aos[i] = types.AttributedObservation{Observation: obs, Observer: commontypes.OracleID(i)}
+		}
+		timestamp := c.CommitReportTimestamp(t, 1, round)
+		shouldReport, report, err := plugins[0].Report(ctx, timestamp, types.Query{}, aos)
+		require.NoError(t, err)
+		require.True(t, shouldReport)
+		for _, p := range plugins {
+			accept, err := p.ShouldAcceptFinalizedReport(ctx, timestamp, report)
+			require.NoError(t, err)
+			require.True(t, accept)
+		}
+
+		// Every oracle gets its turn in the transmission schedule, each round starting with another one, and
+		// transmits unless its plugin finds the report already committed by then.
+		startBlock := c.Dest.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+		first := int(round) % len(oracles)
+		for i := range oracles {
+			o := (first + i) % len(oracles)
+			transmit, err := plugins[o].ShouldTransmitAcceptedReport(ctx, timestamp, report)
+			require.NoError(t, err)
+			if transmit {
+				c.TransmitCommitReportAt(t, oracles, oracles[o], timestamp, report)
+			}
+		}
+		transmitter := c.AssertSingleTransmission(t, oracles, startBlock, report)
+		require.Equal(t, oracles[first].Transmitter.From, transmitter.Transmitter.From)
+	}
+}
+
+func TestCommitReportingPlugin_recommitAfterReorg(t *testing.T) {
+	c := testhelpers.SetupCCIPContracts(t, testhelpers.SourceChainID, testhelpers.SourceChainSelector, testhelpers.DestChainID, testhelpers.DestChainSelector)
+	p := newLaneCommitReportingPlugin(t, c)
+	c.SendDataMessages(t, 2, big.NewInt(200_000))
+
+	ancestor := c.Dest.Chain.Blockchain().CurrentBlock().Number.Uint64()
+	committed := runLaneCommitRound(t, c, p, 1)
+	require.NotNil(t, committed)
+	require.Equal(t, commit_store.CommitStoreInterval{Min: 1, Max: 2}, committed.Interval)
+	root := committed.MerkleRoot
+
+	// Once the commit is reorged out the root is no longer live and its messages have to be committed again.
+	testhelpers.Reorg(t, c.Dest.Chain, ancestor)
+	timestamp, err := c.Dest.CommitStore.GetMerkleRoot(nil, root)
+	require.NoError(t, err)
+	require.Zero(t, timestamp.Uint64())
+	nextSeqNum, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), nextSeqNum)
+
+	// The plugin builds on its inflight report until it expires, which is the one place it learns that the report
+	// never made it.
+	require.Nil(t, runLaneCommitRound(t, c, p, 2))
+	p.inflightReports.cacheExpiry = 0
+	p.inflightReports.expire(p.lggr)
+
+	recommitted := runLaneCommitRound(t, c, p, 3)
+	require.NotNil(t, recommitted)
+	require.Equal(t, root, recommitted.MerkleRoot)
+	c.AssertRootRecommittedAfterReorg(t, root)
+}
+
+func TestCommitReportingPlugin_clockSkewPriceStaleness(t *testing.T) {
+	price := big.NewInt(8e18)
+	testCases := []struct {
+		name string
+		// skew is how far the dest clock is behind the clock of the plugin. The simulated backend does not mine
+		// blocks in the future, so the dest clock cannot be ahead.
+		skew          time.Duration
+		expectUpdated bool
+	}{
+		{name: "clocks in sync", skew: 0},
+		// The heartbeat is measured against the timestamps of the dest chain, so a dest clock lagging by more than
+		// the heartbeat makes every price look due for an update, though the dest chain holds it as fresh.
+		{name: "dest clock behind by more than the heartbeat", skew: 3 * time.Hour, expectUpdated: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := testutils.Context(t)
+			c := testhelpers.SetupCCIPContracts(t, testhelpers.SourceChainID, testhelpers.SourceChainSelector, testhelpers.DestChainID, testhelpers.DestChainSelector)
+			c.SetupSimulatedOracles(t)
+			p := newLaneCommitReportingPlugin(t, c)
+			p.offchainConfig = c.CommitOffchainConfig(t)
+			if tc.expectUpdated {
+				require.Greater(t, tc.skew, p.offchainConfig.FeeUpdateHeartBeat.Duration())
+			}
+
+			p.destPriceRegistry = c.Dest.PriceRegistry
+			destReader := ccipdata.NewMockReader(t)
+			destReader.On("GetTokenPriceUpdatesCreatedAfter", mock.Anything, c.Dest.PriceRegistry.Address(), mock.Anything, 0).
+				Return(func(_ context.Context, _ common.Address, ts time.Time, _ int) ([]ccipdata.Event[price_registry.PriceRegistryUsdPerTokenUpdated], error) {
+					it, err := c.Dest.PriceRegistry.FilterUsdPerTokenUpdated(&bind.FilterOpts{}, nil)
+					require.NoError(t, err)
+					defer it.Close()
+					var updates []ccipdata.Event[price_registry.PriceRegistryUsdPerTokenUpdated]
+					for it.Next() {
+						blockTimestamp := time.Unix(int64(c.Dest.Chain.Blockchain().GetHeaderByNumber(it.Event.Raw.BlockNumber).Time), 0)
+						if !blockTimestamp.Before(ts) {
+							updates = append(updates, ccipdata.Event[price_registry.PriceRegistryUsdPerTokenUpdated]{
+								Data:      *it.Event,
+								BlockMeta: ccipdata.BlockMeta{BlockTimestamp: blockTimestamp, BlockNumber: int64(it.Event.Raw.BlockNumber)},
+							})
+						}
+					}
+					require.NoError(t, it.Error())
+					return updates, nil
+				})
+			p.config.destReader = destReader
+
+			// The price is committed in a block stamped by the skewed dest clock.
+			token := c.Dest.LinkToken.Address()
+			testhelpers.SetChainClock(t, c.Dest.Chain, time.Now().Add(-tc.skew))
+			report, err := abihelpers.EncodeCommitReport(commit_store.CommitStoreCommitReport{
+				PriceUpdates: commit_store.InternalPriceUpdates{
+					TokenPriceUpdates: []commit_store.InternalTokenPriceUpdate{{SourceToken: token, UsdPerToken: price}},
+					UsdPerUnitGas:     big.NewInt(0),
+				},
+			})
+			require.NoError(t, err)
+			tx, err := c.Dest.CommitStoreHelper.Report(c.Dest.User, report, big.NewInt(1))
+			require.NoError(t, err)
+			testhelpers.ConfirmTxs(t, []*gethtypes.Transaction{tx}, c.Dest.Chain)
+			_, err = c.Dest.PriceRegistry.ConvertTokenAmount(nil, token, big.NewInt(1), token)
+			require.NoError(t, err, "price is stale on the dest chain")
+
+			latestTokenPrices, err := p.getLatestTokenPriceUpdates(ctx, time.Now(), false)
+			require.NoError(t, err)
+			obs := CommitObservation{TokenPricesUSD: map[common.Address]*big.Int{token: price}}
+			priceUpdates := p.calculatePriceUpdates([]CommitObservation{obs, obs, obs}, update{}, latestTokenPrices)
+			stale := p.isStaleReport(ctx, p.lggr, commit_store.CommitStoreCommitReport{
+				PriceUpdates: commit_store.InternalPriceUpdates{
+					TokenPriceUpdates: []commit_store.InternalTokenPriceUpdate{{SourceToken: token, UsdPerToken: price}},
+					UsdPerUnitGas:     big.NewInt(0),
+				},
+			}, false, types.ReportTimestamp{Epoch: 2, Round: 1})
+
+			if tc.expectUpdated {
+				assert.Equal(t, []commit_store.InternalTokenPriceUpdate{{SourceToken: token, UsdPerToken: price}}, priceUpdates.TokenPriceUpdates)
+				assert.False(t, stale)
+				return
+			}
+			assert.Empty(t, priceUpdates.TokenPriceUpdates)
+			assert.True(t, stale)
+		})
+	}
+}
+
+func TestCommitReportingPlugin_gasPriceAggregation(t *testing.T) {
+	val1e18 := func(val int64) *big.Int { return new(big.Int).Mul(big.NewInt(1e18), big.NewInt(val)) }
+	gwei := func(val int64) *big.Int { return new(big.Int).Mul(big.NewInt(1e9), big.NewInt(val)) }
+	sourceNative := utils.RandomAddress()
+	testCases := []struct {
+		name    string
+		samples []testhelpers.GasSample
+		// missing is the number of oracles which do not observe a gas price.
+		missing int
+		exp     *big.Int
+	}{
+		{
+			name: "median of a rising series",
+			samples: []testhelpers.GasSample{
+				{GasPriceWei: gwei(10), NativePriceUSD: val1e18(2000)},
+				{GasPriceWei: gwei(20), NativePriceUSD: val1e18(2000)},
+				{GasPriceWei: gwei(30), NativePriceUSD: val1e18(2000)},
+				{GasPriceWei: gwei(40), NativePriceUSD: val1e18(2000)},
+			},
+			exp: big.NewInt(60e12),
+		},
+		{
+			name: "prices converted before the median",
+			samples: []testhelpers.GasSample{
+				{GasPriceWei: gwei(10), NativePriceUSD: val1e18(4000)},
+				{GasPriceWei: gwei(30), NativePriceUSD: val1e18(1000)},
+				{GasPriceWei: gwei(20), NativePriceUSD: val1e18(1000)},
+				{GasPriceWei: gwei(1), NativePriceUSD: val1e18(2000)},
+			},
+			exp: big.NewInt(30e12),
+		},
+		{
+			name: "outlier does not move the median",
+			samples: []testhelpers.GasSample{
+				{GasPriceWei: gwei(20), NativePriceUSD: val1e18(2000)},
+				{GasPriceWei: gwei(21), NativePriceUSD: val1e18(2000)},
+				{GasPriceWei: gwei(10_000), NativePriceUSD: val1e18(2000)},
+			},
+			missing: 1,
+			exp:     big.NewInt(42e12),
+		},
+		{
+			name: "no more than f samples",
+			samples: []testhelpers.GasSample{
+				{GasPriceWei: gwei(20), NativePriceUSD: val1e18(2000)},
+			},
+			missing: 3,
+			exp:     big.NewInt(0),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := testutils.Context(t)
+			observations := make([]CommitObservation, 0, len(tc.samples)+tc.missing)
+			for _, sample := range tc.samples {
+				priceGetter := pricegetter.NewMockPriceGetter(t)
+				priceGetter.On("TokenPricesUSD", ctx, []common.Address{sourceNative}).
+					Return(map[common.Address]*big.Int{sourceNative: sample.NativePriceUSD}, nil)
+				sourceFeeEstimator := mocks.NewEvmFeeEstimator(t)
+				sourceFeeEstimator.On("GetFee", ctx, []byte(nil), uint32(0), assets.NewWei(big.NewInt(0))).
+					Return(gas.EvmFee{Legacy: assets.NewWei(sample.GasPriceWei)}, uint32(0), nil)
+
+				p := &CommitReportingPlugin{}
+				p.config.sourceNative = sourceNative
+				p.config.priceGetter = priceGetter
+				p.config.sourceFeeEstimator = sourceFeeEstimator
+				sourceGasPriceUSD, _, err := p.generatePriceUpdates(ctx, logger.TestLogger(t), map[common.Address]uint8{})
+				require.NoError(t, err)
+				observations = append(observations, CommitObservation{SourceGasPriceUSD: sourceGasPriceUSD})
+			}
+			for i := 0; i < tc.missing; i++ {
+				observations = append(observations, CommitObservation{})
+			}
+
+			p := &CommitReportingPlugin{}
+			p.lggr = logger.TestLogger(t)
+			p.F = 1
+			priceUpdates := p.calculatePriceUpdates(observations, update{}, nil)
+			assert.Equal(t, tc.exp.String(), priceUpdates.UsdPerUnitGas.String())
+			testhelpers.AssertCommittedGasPrice(t, tc.samples, p.F, priceUpdates.UsdPerUnitGas)
+		})
+	}
+}
+
 func TestCommitReportingPlugin_getLatestGasPriceUpdate(t *testing.T) {
 	now := time.Now()
 
@@ -1419,3 +2099,130 @@ type leafHasher123 struct{}
 func (h leafHasher123) HashLeaf(_ gethtypes.Log) ([32]byte, error) {
 	return [32]byte{123}, nil
 }
+
+// newLaneCommitReportingPlugin returns a commit plugin for the lane of c, which reads the send requests of the
+// onRamp straight from the source chain, as deep as its SourceFinalityDepth, and leaves prices out of its reports.
+func newLaneCommitReportingPlugin(t *testing.T, c testhelpers.CCIPContracts) *CommitReportingPlugin {
+	onRampAddress := c.Source.OnRamp.Address()
+	sendRequests := func(min, max uint64, confs int) []ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested] {
+		head := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+		if head < uint64(confs) {
+			return nil
+		}
+		finalized := head - uint64(confs)
+		it, err := c.Source.OnRamp.FilterCCIPSendRequested(&bind.FilterOpts{End: &finalized})
+		require.NoError(t, err)
+		defer it.Close()
+		var reqs []ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested]
+		for it.Next() {
+			if seqNum := it.Event.Message.SequenceNumber; seqNum >= min && seqNum <= max {
+				reqs = append(reqs, ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested]{
+					Data:      *it.Event,
+					BlockMeta: ccipdata.BlockMeta{BlockNumber: int64(it.Event.Raw.BlockNumber)},
+				})
+			}
+		}
+		require.NoError(t, it.Error())
+		return reqs
+	}
+	sourceReader := ccipdata.NewMockReader(t)
+	sourceReader.On("GetSendRequestsGteSeqNum", mock.Anything, onRampAddress, mock.Anything, false, mock.Anything).
+		Return(func(_ context.Context, _ common.Address, seqNum uint64, _ bool, confs int) ([]ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested], error) {
+			return sendRequests(seqNum, math.MaxUint64, confs), nil
+		}).Maybe()
+	sourceReader.On("GetSendRequestsBetweenSeqNums", mock.Anything, onRampAddress, mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, _ common.Address, min, max uint64, confs int) ([]ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested], error) {
+			return sendRequests(min, max, confs), nil
+		}).Maybe()
+	destPriceRegistry, destPriceRegistryAddress := testhelpers.NewFakePriceRegistry(t)
+	destReader := ccipdata.NewMockReader(t)
+	destReader.On("GetGasPriceUpdatesCreatedAfter", mock.Anything, destPriceRegistryAddress, c.Source.ChainSelector, mock.Anything, 0).Return(nil, nil).Maybe()
+	destReader.On("GetTokenPriceUpdatesCreatedAfter", mock.Anything, destPriceRegistryAddress, mock.Anything, 0).Return(nil, nil).Maybe()
+
+	p := &CommitReportingPlugin{}
+	p.lggr = logger.TestLogger(t)
+	p.F = 1
+	p.inflightReports = newInflightCommitReportsContainer(time.Hour)
+	p.destPriceRegistry = destPriceRegistry
+	p.config.commitStore = c.Dest.CommitStore
+	p.config.destReader = destReader
+	p.config.sourceReader = sourceReader
+	p.config.onRampAddress = onRampAddress
+	p.config.sourceChainSelector = c.Source.ChainSelector
+	p.config.leafHasher = hashlib.NewLeafHasher(c.Source.ChainSelector, c.Dest.ChainSelector, onRampAddress, hashlib.NewKeccakCtx())
+	return p
+}
+
+// runLaneCommitRound runs round round of epoch 1 of p, a plugin for the lane of c, in a DON whose oracles observe
+// what p does. Unless p has nothing to report or rejects its report, the report is committed through the
+// commitStore helper, standing in for the transmitter, and returned.
+func runLaneCommitRound(t *testing.T, c testhelpers.CCIPContracts, p *CommitReportingPlugin, round uint8) *commit_store.CommitStoreCommitReport {
+	ctx := testutils.Context(t)
+	min, max, err := p.calculateMinMaxSequenceNumbers(ctx, p.lggr)
+	require.NoError(t, err)
+	if min == 0 {
+		return nil
+	}
+	obs, err := CommitObservation{Interval: commit_store.CommitStoreInterval{Min: min, Max: max}}.Marshal()
+	require.NoError(t, err)
+	aos := []types.AttributedObservation{{Observation: obs}, {Observation: obs}, {Observation: obs}}
+	timestamp := types.ReportTimestamp{Epoch: 1, Round: round}
+	shouldReport, report, err := p.Report(ctx, timestamp, types.Query{}, aos)
+	require.NoError(t, err)
+	require.True(t, shouldReport)
+
+	if accept, err := p.ShouldAcceptFinalizedReport(ctx, timestamp, report); err != nil || !accept {
+		require.NoError(t, err)
+		return nil
+	}
+	transmit, err := p.ShouldTransmitAcceptedReport(ctx, timestamp, report)
+	require.NoError(t, err)
+	if !transmit {
+		return nil
+	}
+	tx, err := c.Dest.CommitStoreHelper.Report(c.Dest.User, report, big.NewInt(int64(round)))
+	require.NoError(t, err)
+	testhelpers.ConfirmTxs(t, []*gethtypes.Transaction{tx}, c.Dest.Chain)
+	decoded, err := abihelpers.DecodeCommitReport(report)
+	require.NoError(t, err)
+	return &decoded
+}
+
+// assertRoundCompletesAfterRestart asserts that the restarted plugin p still transmits report, accepted before
+// the restart, and that once the report is committed it is neither transmitted again nor observed again.
+func assertRoundCompletesAfterRestart(t *testing.T, p *CommitReportingPlugin, commitStore *testhelpers.FakeCommitStore, report commit_store.CommitStoreCommitReport) {
+	ctx := testutils.Context(t)
+	encodedReport, err := abihelpers.EncodeCommitReport(report)
+	require.NoError(t, err)
+
+	shouldTransmit, err := p.ShouldTransmitAcceptedReport(ctx, types.ReportTimestamp{}, encodedReport)
+	require.NoError(t, err)
+	require.True(t, shouldTransmit, "report accepted before the restart is not transmitted")
+
+	commitStore.SetNextSequenceNumber(report.Interval.Max + 1)
+	shouldTransmit, err = p.ShouldTransmitAcceptedReport(ctx, types.ReportTimestamp{}, encodedReport)
+	require.NoError(t, err)
+	assert.False(t, shouldTransmit, "committed report is transmitted again")
+	shouldAccept, err := p.ShouldAcceptFinalizedReport(ctx, types.ReportTimestamp{}, encodedReport)
+	require.NoError(t, err)
+	assert.False(t, shouldAccept, "committed report is accepted again")
+
+	inflightMin, _, err := p.nextMinSeqNum(ctx, p.lggr)
+	require.NoError(t, err)
+	assert.Equal(t, report.Interval.Max+1, inflightMin, "committed messages are observed again")
+}
+
+// retryOnFault runs step until it makes none of its calls to backend fail, and returns the result of that run.
+// Runs in which a call failed must return false.
+func retryOnFault(t *testing.T, backend *testhelpers.FaultInjectingBackend, step func() bool) bool {
+	for attempt := 0; attempt < 20; attempt++ {
+		faults := backend.Faults()
+		ok := step()
+		if backend.Faults() == faults {
+			return ok
+		}
+		require.False(t, ok, "step succeeded despite a failed rpc call")
+	}
+	require.FailNow(t, "step failed on every attempt")
+	return false
+}
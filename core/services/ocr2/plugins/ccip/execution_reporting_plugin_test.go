@@ -15,7 +15,9 @@ import (
 
 	"github.com/cometbft/cometbft/libs/rand"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
 	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
 	"github.com/smartcontractkit/libocr/commontypes"
 	"github.com/smartcontractkit/libocr/offchainreporting2/types"
@@ -39,6 +41,7 @@ import (
 	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal"
 	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/cache"
 	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/ccipdata"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/hashlib"
 	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/testhelpers"
 	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/tokendata"
 	"github.com/smartcontractkit/chainlink/v2/core/utils"
@@ -657,6 +660,207 @@ func TestExecutionReportingPlugin_buildBatch(t *testing.T) {
 	}
 }
 
+func TestExecutionReportingPlugin_buildBatchBoostsDelayedFees(t *testing.T) {
+	c, _ := testhelpers.SetupChain(t)
+	offRamp, _ := testhelpers.NewFakeOffRamp(t)
+	onRamp, err := evm_2_evm_onramp.NewEVM2EVMOnRamp(common.HexToAddress("0x1"), c)
+	require.NoError(t, err)
+	lggr := logger.TestLogger(t)
+
+	sender := common.HexToAddress("0xa")
+	destNative := common.HexToAddress("0xb")
+	srcNative := common.HexToAddress("0xc")
+	const boost = 0.5
+	plugin := ExecutionReportingPlugin{
+		config: ExecutionPluginConfig{
+			offRamp: offRamp,
+			onRamp:  onRamp,
+		},
+		destWrappedNative: destNative,
+		offchainConfig: ccipconfig.ExecOffchainConfig{
+			SourceFinalityDepth:         5,
+			DestOptimisticConfirmations: 1,
+			DestFinalityDepth:           5,
+			BatchGasLimit:               5_000_000,
+			RelativeBoostPerWaitHour:    boost,
+			MaxGasPrice:                 1,
+		},
+		lggr: lggr,
+	}
+
+	gasLimit := big.NewInt(200_000)
+	destGasPrice := big.NewInt(1e9)
+	destNativePrice := new(big.Int).Mul(big.NewInt(2000), big.NewInt(1e18))
+	execCostUsd := computeExecCost(gasLimit, destGasPrice, destNativePrice)
+	// The fee is paid in a token worth 1 USD and covers a quarter of the exec cost, which a boost of 0.5 per hour
+	// makes up for after 6 hours.
+	fee := new(big.Int).Div(execCostUsd, big.NewInt(4))
+
+	// The delays are whole multiples of 1/16 hours, for which the boosted fee is exact.
+	testCases := []struct {
+		delay time.Duration
+		// boostedFee is the boosted fee in 32nds of fee.
+		boostedFee int64
+		executed   bool
+	}{
+		{delay: 0, boostedFee: 32},
+		{delay: 225 * time.Second, boostedFee: 33},
+		{delay: 30 * time.Minute, boostedFee: 40},
+		{delay: 5 * time.Hour, boostedFee: 112},
+		{delay: 6*time.Hour + 225*time.Second, boostedFee: 129, executed: true},
+		{delay: 48 * time.Hour, boostedFee: 800, executed: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.delay.String(), func(t *testing.T) {
+			boostedFee := new(big.Int).Div(new(big.Int).Mul(fee, big.NewInt(tc.boostedFee)), big.NewInt(32))
+			assert.Equal(t, boostedFee, waitBoostedFee(tc.delay, fee, boost))
+
+			offRamp.SetSenderNonces(map[common.Address]uint64{sender: 0})
+			msg := internal.EVM2EVMOnRampCCIPSendRequestedWithMeta{
+				InternalEVM2EVMMessage: evm_2_evm_offramp.InternalEVM2EVMMessage{
+					SequenceNumber: 1,
+					FeeTokenAmount: fee,
+					Sender:         sender,
+					Nonce:          1,
+					GasLimit:       gasLimit,
+					FeeToken:       srcNative,
+				},
+				BlockTimestamp: time.Now().Add(-tc.delay),
+			}
+			seqNrs := plugin.buildBatch(
+				context.Background(),
+				lggr,
+				commitReportWithSendRequests{sendRequestsWithMeta: []internal.EVM2EVMOnRampCCIPSendRequestedWithMeta{msg}},
+				nil,
+				big.NewInt(0),
+				map[common.Address]*big.Int{srcNative: big.NewInt(1e18)},
+				map[common.Address]*big.Int{destNative: destNativePrice},
+				func() (*big.Int, error) { return destGasPrice, nil },
+				nil,
+				nil,
+			)
+
+			// The message waits a little longer in buildBatch than delay, which does not matter away from 6 hours.
+			if tc.executed {
+				assert.Equal(t, []ObservedMessage{{SeqNr: 1}}, seqNrs)
+			} else {
+				assert.Empty(t, seqNrs)
+			}
+		})
+	}
+}
+
+func TestExecutionReportingPlugin_laneWithBlockTimes(t *testing.T) {
+	const duration = time.Hour
+	testCases := []struct {
+		name                      string
+		srcInterval, destInterval time.Duration
+	}{
+		{name: "fast source", srcInterval: 40 * time.Second, destInterval: 2 * time.Minute},
+		{name: "fast dest", srcInterval: 2 * time.Minute, destInterval: 40 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := testutils.Context(t)
+			c := testhelpers.SetupCCIPContracts(t, testhelpers.SourceChainID, testhelpers.SourceChainSelector, testhelpers.DestChainID, testhelpers.DestChainSelector)
+			oracles := c.SetupSimulatedOracles(t)
+			commitPlugin := newLaneCommitReportingPlugin(t, c)
+			commitPlugin.offchainConfig.SourceFinalityDepth = 4
+			execPlugin := newLaneExecutionReportingPlugin(t, c)
+			threshold := execPlugin.onchainConfig.PermissionLessExecutionThresholdDuration()
+			require.Less(t, threshold, duration/2)
+
+			// The plugins measure the age of roots and messages against the wall clock, at which the run ends, so
+			// that the roots committed early in the run are past the permissionless execution threshold.
+			start := time.Now().Add(-duration)
+			lateSend := start.Add(duration - threshold/2)
+			var early, late uint64
+			srcRound := func(t *testing.T, now time.Time) {
+				switch {
+				case early == 0:
+					// Prices are stamped by the source clock, which the run moves years ahead.
+					linkPrice, err := c.Source.PriceRegistry.GetTokenPrice(nil, c.Source.LinkToken.Address())
+					require.NoError(t, err)
+					gasPrice, err := c.Source.PriceRegistry.GetDestinationChainGasPrice(nil, c.Dest.ChainSelector)
+					require.NoError(t, err)
+					tx, err := c.Source.PriceRegistry.UpdatePrices(c.Source.User, price_registry.InternalPriceUpdates{
+						TokenPriceUpdates: []price_registry.InternalTokenPriceUpdate{{SourceToken: c.Source.LinkToken.Address(), UsdPerToken: linkPrice.Value}},
+						DestChainSelector: c.Dest.ChainSelector,
+						UsdPerUnitGas:     gasPrice.Value,
+					})
+					require.NoError(t, err)
+					testhelpers.ConfirmTxs(t, []*gethtypes.Transaction{tx}, c.Source.Chain)
+					c.SendDataMessages(t, 1, big.NewInt(100_000))
+					early = 1
+				case late == 0 && !now.Before(lateSend):
+					c.SendDataMessages(t, 1, big.NewInt(100_000))
+					late = 2
+				}
+			}
+
+			var round uint8
+			var manuallyExecuted bool
+			destRound := func(t *testing.T, now time.Time) {
+				round++
+				if late != 0 && !manuallyExecuted {
+					// The DON has left the early message, whose root is past the permissionless execution threshold,
+					// and it holds back the late one from the same sender until it is manually executed.
+					c.AssertExecStateForSeqNum(t, early, abihelpers.ExecutionStateUntouched)
+					tree, msgs := testhelpers.BuildSortedTree(t, c.SendRequestedMessages(t, 0)[:1])
+					tx, err := c.Dest.OffRamp.ManuallyExecute(c.Dest.User, testhelpers.BuildExecutionReport(t, tree, msgs, []int{0}), []*big.Int{big.NewInt(0)})
+					require.NoError(t, err)
+					testhelpers.ConfirmTxs(t, []*gethtypes.Transaction{tx}, c.Dest.Chain)
+					manuallyExecuted = true
+				}
+				runLaneCommitRound(t, c, commitPlugin, round)
+
+				obs, err := execPlugin.Observation(ctx, types.ReportTimestamp{Epoch: 1, Round: round}, types.Query{})
+				require.NoError(t, err)
+				aos := []types.AttributedObservation{{Observation: obs}, {Observation: obs}, {Observation: obs}}
+				shouldReport, report, err := execPlugin.Report(ctx, types.ReportTimestamp{Epoch: 1, Round: round}, types.Query{}, aos)
+				require.NoError(t, err)
+				if !shouldReport {
+					return
+				}
+				accept, err := execPlugin.ShouldAcceptFinalizedReport(ctx, types.ReportTimestamp{Epoch: 1, Round: round}, report)
+				require.NoError(t, err)
+				require.True(t, accept)
+				transmit, err := execPlugin.ShouldTransmitAcceptedReport(ctx, types.ReportTimestamp{Epoch: 1, Round: round}, report)
+				require.NoError(t, err)
+				require.True(t, transmit)
+				decoded, err := abihelpers.DecodeExecutionReport(report)
+				require.NoError(t, err)
+				_, err = c.TransmitExecutionReport(t, oracles[0], decoded)
+				require.NoError(t, err)
+			}
+
+			srcHead := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+			c.RunLaneWithBlockTimes(t, tc.srcInterval, tc.destInterval, start, duration, srcRound, destRound)
+
+			// Every block of the run is stamped at the interval of its chain, so the clocks of the two chains only
+			// agree at the end of the run, each within an interval of its end.
+			end := start.Add(duration)
+			for _, clock := range []struct {
+				chain    *backends.SimulatedBackend
+				interval time.Duration
+			}{{c.Source.Chain, tc.srcInterval}, {c.Dest.Chain, tc.destInterval}} {
+				require.WithinDuration(t, end, testhelpers.ChainClock(clock.chain), clock.interval)
+			}
+			require.GreaterOrEqual(t, c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()-srcHead, uint64(duration/tc.srcInterval))
+
+			nextSeqNum, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+			require.NoError(t, err)
+			require.Equal(t, late+1, nextSeqNum)
+			require.True(t, manuallyExecuted)
+			c.AssertExecStateForSeqNum(t, early, abihelpers.ExecutionStateSuccess)
+			c.AssertExecStateForSeqNum(t, late, abihelpers.ExecutionStateSuccess)
+		})
+	}
+}
+
 func TestExecutionReportingPlugin_isRateLimitEnoughForTokenPool(t *testing.T) {
 	testCases := []struct {
 		name                    string
@@ -1695,6 +1899,134 @@ func Test_commitReportWithSendRequests_sendReqFits(t *testing.T) {
 }
 
 // generateExecutionReport generates an execution report that can be used in tests
+// newLaneExecutionReportingPlugin returns an exec plugin for the lane of c, as configured by SetupSimulatedOracles,
+// which reads the logs of the lane straight from its chains, with the timestamps of their blocks, and estimates
+// dest gas at 1 gwei.
+func newLaneExecutionReportingPlugin(t *testing.T, c testhelpers.CCIPContracts) *ExecutionReportingPlugin {
+	onchainConfig, err := abihelpers.DecodeAbiStruct[ccipconfig.ExecOnchainConfig](c.CreateDefaultExecOnchainConfig(t))
+	require.NoError(t, err)
+	offchainConfig, err := ccipconfig.DecodeOffchainConfig[ccipconfig.ExecOffchainConfig](c.CreateDefaultExecOffchainConfig(t))
+	require.NoError(t, err)
+	blockTime := func(chain *backends.SimulatedBackend, blockNumber uint64) time.Time {
+		return time.Unix(int64(chain.Blockchain().GetHeaderByNumber(blockNumber).Time), 0)
+	}
+
+	sourceReader := ccipdata.NewMockReader(t)
+	sourceReader.On("GetSendRequestsBetweenSeqNums", mock.Anything, c.Source.OnRamp.Address(), mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, _ common.Address, min, max uint64, _ int) ([]ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested], error) {
+			it, err := c.Source.OnRamp.FilterCCIPSendRequested(&bind.FilterOpts{})
+			require.NoError(t, err)
+			defer it.Close()
+			var reqs []ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested]
+			for it.Next() {
+				if seqNum := it.Event.Message.SequenceNumber; seqNum >= min && seqNum <= max {
+					reqs = append(reqs, ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested]{
+						Data:      *it.Event,
+						BlockMeta: ccipdata.BlockMeta{BlockNumber: int64(it.Event.Raw.BlockNumber), BlockTimestamp: blockTime(c.Source.Chain, it.Event.Raw.BlockNumber)},
+					})
+				}
+			}
+			require.NoError(t, it.Error())
+			return reqs, nil
+		}).Maybe()
+
+	acceptedReports := func(keep func(ccipdata.Event[commit_store.CommitStoreReportAccepted]) bool) []ccipdata.Event[commit_store.CommitStoreReportAccepted] {
+		it, err := c.Dest.CommitStore.FilterReportAccepted(&bind.FilterOpts{})
+		require.NoError(t, err)
+		defer it.Close()
+		var reports []ccipdata.Event[commit_store.CommitStoreReportAccepted]
+		for it.Next() {
+			report := ccipdata.Event[commit_store.CommitStoreReportAccepted]{
+				Data:      *it.Event,
+				BlockMeta: ccipdata.BlockMeta{BlockNumber: int64(it.Event.Raw.BlockNumber), BlockTimestamp: blockTime(c.Dest.Chain, it.Event.Raw.BlockNumber)},
+			}
+			if keep(report) {
+				reports = append(reports, report)
+			}
+		}
+		require.NoError(t, it.Error())
+		return reports
+	}
+	destReader := ccipdata.NewMockReader(t)
+	destReader.On("GetAcceptedCommitReportsGteTimestamp", mock.Anything, c.Dest.CommitStore.Address(), mock.Anything, mock.Anything).
+		Return(func(_ context.Context, _ common.Address, ts time.Time, _ int) ([]ccipdata.Event[commit_store.CommitStoreReportAccepted], error) {
+			return acceptedReports(func(report ccipdata.Event[commit_store.CommitStoreReportAccepted]) bool {
+				return !report.BlockTimestamp.Before(ts)
+			}), nil
+		}).Maybe()
+	destReader.On("GetAcceptedCommitReportsGteSeqNum", mock.Anything, c.Dest.CommitStore.Address(), mock.Anything, mock.Anything).
+		Return(func(_ context.Context, _ common.Address, seqNum uint64, _ int) ([]ccipdata.Event[commit_store.CommitStoreReportAccepted], error) {
+			return acceptedReports(func(report ccipdata.Event[commit_store.CommitStoreReportAccepted]) bool {
+				return report.Data.Report.Interval.Max >= seqNum
+			}), nil
+		}).Maybe()
+	destReader.On("GetExecutionStateChangesBetweenSeqNums", mock.Anything, c.Dest.OffRamp.Address(), mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, _ common.Address, min, max uint64, _ int) ([]ccipdata.Event[evm_2_evm_offramp.EVM2EVMOffRampExecutionStateChanged], error) {
+			it, err := c.Dest.OffRamp.FilterExecutionStateChanged(&bind.FilterOpts{}, nil, nil)
+			require.NoError(t, err)
+			defer it.Close()
+			var changes []ccipdata.Event[evm_2_evm_offramp.EVM2EVMOffRampExecutionStateChanged]
+			for it.Next() {
+				if seqNum := it.Event.SequenceNumber; seqNum >= min && seqNum <= max {
+					changes = append(changes, ccipdata.Event[evm_2_evm_offramp.EVM2EVMOffRampExecutionStateChanged]{
+						Data:      *it.Event,
+						BlockMeta: ccipdata.BlockMeta{BlockNumber: int64(it.Event.Raw.BlockNumber)},
+					})
+				}
+			}
+			require.NoError(t, it.Error())
+			return changes, nil
+		}).Maybe()
+	destReader.On("LatestBlock", mock.Anything).
+		Return(func(context.Context) (int64, error) {
+			return c.Dest.Chain.Blockchain().CurrentBlock().Number.Int64(), nil
+		}).Maybe()
+
+	sourceFeeTokens, err := c.Source.PriceRegistry.GetFeeTokens(nil)
+	require.NoError(t, err)
+	cachedSourceFeeTokens := cache.NewMockAutoSync[[]common.Address](t)
+	cachedSourceFeeTokens.On("Get", mock.Anything).Return(sourceFeeTokens, nil).Maybe()
+	destFeeTokens, err := c.Dest.PriceRegistry.GetFeeTokens(nil)
+	require.NoError(t, err)
+	sourceTokens, err := c.Dest.OffRamp.GetSupportedTokens(nil)
+	require.NoError(t, err)
+	supportedTokens := make(map[common.Address]common.Address, len(sourceTokens))
+	for _, sourceToken := range sourceTokens {
+		supportedTokens[sourceToken], err = c.Dest.OffRamp.GetDestinationToken(nil, sourceToken)
+		require.NoError(t, err)
+	}
+	cachedDestTokens := cache.NewMockAutoSync[cache.CachedTokens](t)
+	cachedDestTokens.On("Get", mock.Anything).Return(cache.CachedTokens{SupportedTokens: supportedTokens, FeeTokens: destFeeTokens}, nil).Maybe()
+	destGasEstimator := mocks.NewEvmFeeEstimator(t)
+	destGasEstimator.On("GetFee", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(gas.EvmFee{Legacy: assets.GWei(1)}, uint32(0), nil).Maybe()
+
+	p := &ExecutionReportingPlugin{}
+	p.lggr = logger.TestLogger(t)
+	p.F = 1
+	p.inflightReports = newInflightExecReportsContainer(offchainConfig.InflightCacheExpiry.Duration())
+	p.snoozedRoots = cache.NewSnoozedRoots(onchainConfig.PermissionLessExecutionThresholdDuration(), offchainConfig.RootSnoozeTime.Duration())
+	p.onchainConfig = onchainConfig
+	p.offchainConfig = offchainConfig
+	p.destPriceRegistry = c.Dest.PriceRegistry
+	p.destWrappedNative = c.Dest.WrappedNative.Address()
+	p.cachedSourceFeeTokens = cachedSourceFeeTokens
+	p.cachedDestTokens = cachedDestTokens
+	p.customTokenPoolFactory = func(ctx context.Context, poolAddress common.Address, contractBackend bind.ContractBackend) (custom_token_pool.CustomTokenPoolInterface, error) {
+		return custom_token_pool.NewCustomTokenPool(poolAddress, contractBackend)
+	}
+	p.config.sourceReader = sourceReader
+	p.config.destReader = destReader
+	p.config.onRamp = c.Source.OnRamp
+	p.config.offRamp = c.Dest.OffRamp
+	p.config.commitStore = c.Dest.CommitStore
+	p.config.sourcePriceRegistry = c.Source.PriceRegistry
+	p.config.sourceWrappedNativeToken = c.Source.WrappedNative.Address()
+	p.config.destGasEstimator = destGasEstimator
+	p.config.leafHasher = hashlib.NewLeafHasher(c.Source.ChainSelector, c.Dest.ChainSelector, c.Source.OnRamp.Address(), hashlib.NewKeccakCtx())
+	return p
+}
+
 func generateExecutionReport(t *testing.T, numMsgs, tokensPerMsg, bytesPerMsg int) evm_2_evm_offramp.InternalExecutionReport {
 	messages := make([]evm_2_evm_offramp.InternalEVM2EVMMessage, numMsgs)
 
@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core/types"
 
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
 	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
 	"github.com/smartcontractkit/chainlink/v2/core/utils"
 )
@@ -30,11 +31,17 @@ type LeafHasher struct {
 
 func NewLeafHasher(sourceChainSelector uint64, destChainSelector uint64, onRampId common.Address, ctx Ctx[[32]byte]) *LeafHasher {
 	return &LeafHasher{
-		metaDataHash: GetMetaDataHash(ctx, ctx.Hash([]byte("EVM2EVMMessageHashV2")), sourceChainSelector, onRampId, destChainSelector),
+		metaDataHash: GetMessageMetaDataHash(ctx, sourceChainSelector, onRampId, destChainSelector),
 		ctx:          ctx,
 	}
 }
 
+// GetMessageMetaDataHash returns the metadata hash of the lane from sourceChainSelector to destChainSelector through
+// onRampId, which is part of the hash of every EVM2EVM message sent on it.
+func GetMessageMetaDataHash(ctx Ctx[[32]byte], sourceChainSelector uint64, onRampId common.Address, destChainSelector uint64) [32]byte {
+	return GetMetaDataHash(ctx, ctx.Hash([]byte("EVM2EVMMessageHashV2")), sourceChainSelector, onRampId, destChainSelector)
+}
+
 var _ LeafHasherInterface[[32]byte] = &LeafHasher{}
 
 func (t *LeafHasher) HashLeaf(log types.Log) ([32]byte, error) {
@@ -42,7 +49,12 @@ func (t *LeafHasher) HashLeaf(log types.Log) ([32]byte, error) {
 	if err != nil {
 		return [32]byte{}, err
 	}
+	return HashMessage(t.ctx, t.metaDataHash, message)
+}
 
+// HashMessage returns the hash of message under the metadata hash of its lane, which is both its merkle leaf and
+// its message ID.
+func HashMessage(ctx Ctx[[32]byte], metaDataHash [32]byte, message *evm_2_evm_offramp.InternalEVM2EVMMessage) ([32]byte, error) {
 	encodedTokens, err := abihelpers.TokenAmountsArgs.PackValues([]interface{}{message.TokenAmounts})
 	if err != nil {
 		return [32]byte{}, err
@@ -81,7 +93,7 @@ func (t *LeafHasher) HashLeaf(log types.Log) ([32]byte, error) {
 	if err != nil {
 		return [32]byte{}, err
 	}
-	fixedSizeValuesHash := t.ctx.Hash(packedFixedSizeValues)
+	fixedSizeValuesHash := ctx.Hash(packedFixedSizeValues)
 
 	packedValues, err := utils.ABIEncode(
 		`[
@@ -93,14 +105,14 @@ func (t *LeafHasher) HashLeaf(log types.Log) ([32]byte, error) {
 {"name": "sourceTokenDataHash", "type":"bytes32"}
 ]`,
 		LeafDomainSeparator,
-		t.metaDataHash,
+		metaDataHash,
 		fixedSizeValuesHash,
-		t.ctx.Hash(message.Data),
-		t.ctx.Hash(encodedTokens),
-		t.ctx.Hash(encodedSourceTokenData),
+		ctx.Hash(message.Data),
+		ctx.Hash(encodedTokens),
+		ctx.Hash(encodedSourceTokenData),
 	)
 	if err != nil {
 		return [32]byte{}, err
 	}
-	return t.ctx.Hash(packedValues), nil
+	return ctx.Hash(packedValues), nil
 }
@@ -0,0 +1,44 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/generated/link_token_interface"
+)
+
+// approvalResetGuard rejects approvals that change an allowance from a nonzero amount to another nonzero amount.
+const approvalResetGuard = `
+DUP1
+SLOAD
+ISZERO
+JUMPI @approveAllowed
+PUSH 36
+CALLDATALOAD
+ISZERO
+JUMPI @approveAllowed
+JUMP @fail
+approveAllowed:
+`
+
+// DeployApprovalResetToken deploys an ERC20 that, like USDT, reverts approvals from a nonzero allowance to another
+// nonzero allowance, which must be reset to zero first. The whole supply is minted to owner. The token is returned
+// bound as a LinkToken, which covers the ERC20 functions it implements.
+func DeployApprovalResetToken(t *testing.T, chain *backends.SimulatedBackend, owner *bind.TransactOpts) (*link_token_interface.LinkToken, common.Address) {
+	return deployToken(t, chain, owner, feeOnTransferTokenCode(t, 0, approvalResetGuard))
+}
+
+// AssertAllowanceReset asserts that spender has no allowance of token left from owner, so that owner can approve
+// it again without resetting the allowance first. Neither the router nor the pools approve tokens themselves, and
+// the router spends the whole amount of every token it transfers, leaving no allowance behind as long as senders
+// approve exactly what they send.
+func AssertAllowanceReset(t *testing.T, token *link_token_interface.LinkToken, owner, spender common.Address) {
+	allowance, err := token.Allowance(nil, owner, spender)
+	require.NoError(t, err)
+	require.Zero(t, allowance.Cmp(big.NewInt(0)), "%s has %s allowance left from %s", spender, allowance, owner)
+}
@@ -0,0 +1,74 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestApprovalResetToken(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	token, tokenAddress := DeployApprovalResetToken(t, c.Source.Chain, c.Source.User)
+
+	// The token rejects changing a nonzero allowance to another nonzero one.
+	spender := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	tx, err := token.Approve(c.Source.User, spender, big.NewInt(1))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	_, err = token.Approve(c.Source.User, spender, big.NewInt(2))
+	require.Error(t, err)
+	tx, err = token.Approve(c.Source.User, spender, big.NewInt(0))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	AssertAllowanceReset(t, token, c.Source.User.From, spender)
+
+	_, destToken, err := c.SetupLockAndMintTokenPool(tokenAddress, "Wrapped approval reset token", "WART")
+	require.NoError(t, err)
+	// The offRamp values the tokens it releases at their dest price.
+	tx, err = c.Dest.PriceRegistry.UpdatePrices(c.Dest.User, tokenPriceUpdate(destToken.Address(), big.NewInt(1e18)))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	receiver := c.Dest.Receivers[0].Receiver.Address()
+	amount := big.NewInt(1e18)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, receiver),
+		Data:         []byte{},
+		TokenAmounts: []router.ClientEVMTokenAmount{{Token: tokenAddress, Amount: amount}},
+		FeeToken:     c.Source.LinkToken.Address(),
+		ExtraArgs:    extraArgs,
+	}
+	tx, err = c.Source.LinkToken.Approve(c.Source.User, c.Source.Router.Address(), HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+
+	// Every send approves the router for exactly the amount it spends, which needs no reset in between.
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	for i := 0; i < 2; i++ {
+		tx, err = token.Approve(c.Source.User, c.Source.Router.Address(), amount)
+		require.NoError(t, err)
+		ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+		c.SendRequest(t, msg)
+		AssertAllowanceReset(t, token, c.Source.User.From, c.Source.Router.Address())
+	}
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 2)
+
+	tree := c.CommitMessages(t, msgs)
+	_, err = c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0, 1}))
+	require.NoError(t, err)
+	for _, msg := range msgs {
+		c.AssertExecStateForSeqNum(t, msg.SequenceNumber, abihelpers.ExecutionStateSuccess)
+	}
+	released, err := destToken.BalanceOf(nil, receiver)
+	require.NoError(t, err)
+	require.Equal(t, new(big.Int).Mul(amount, big.NewInt(2)).String(), released.String())
+}
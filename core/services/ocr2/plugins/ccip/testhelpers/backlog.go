@@ -0,0 +1,62 @@
+package testhelpers
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/commit_store"
+)
+
+// backlogMessagesPerBlock is the number of messages GenerateBacklogExceedingCommitCap includes per source block,
+// which keeps the blocks well within the gas limit of the simulated chain.
+const backlogMessagesPerBlock = 32
+
+// GenerateBacklogExceedingCommitCap sends data-only messages from sender, paid for in LINK, until the backlog
+// of the lane holds at least one and a half times cap messages, so that committing it takes more than one root
+// of up to cap messages, with the last root a partial one. It returns the sequence numbers of the backlog in
+// sending order.
+func (c *CCIPContracts) GenerateBacklogExceedingCommitCap(t *testing.T, sender *bind.TransactOpts, cap int) []uint64 {
+	require.Positive(t, cap)
+	return c.GenerateMixedTraffic(t, sender, TrafficSpec{
+		Blocks:           (cap+cap/2)/backlogMessagesPerBlock + 1,
+		MessagesPerBlock: backlogMessagesPerBlock,
+		DataSizes:        []int{0},
+		FeeTokens:        []common.Address{c.Source.LinkToken.Address()},
+	})
+}
+
+// AssertMultipleRootsCommitted asserts that the backlog with the given sequence numbers, as returned by
+// GenerateBacklogExceedingCommitCap, was committed in more than one root, each of which covers at most cap
+// messages. The intervals of the roots must be disjoint and together span the whole backlog without gaps.
+// Only the roots committed since the start of the backlog are considered.
+func (c *CCIPContracts) AssertMultipleRootsCommitted(t *testing.T, seqNums []uint64, cap int) {
+	require.NotEmpty(t, seqNums)
+	it, err := c.Dest.CommitStore.FilterReportAccepted(&bind.FilterOpts{})
+	require.NoError(t, err)
+	defer it.Close()
+	var intervals []commit_store.CommitStoreInterval
+	for it.Next() {
+		interval := it.Event.Report.Interval
+		if interval.Max < seqNums[0] {
+			continue
+		}
+		require.NotEqual(t, [32]byte{}, it.Event.Report.MerkleRoot, "interval [%d, %d] was committed without a root", interval.Min, interval.Max)
+		intervals = append(intervals, interval)
+	}
+	require.NoError(t, it.Error())
+
+	require.Greater(t, len(intervals), 1, "backlog of %d messages was committed in a single root", len(seqNums))
+	next := seqNums[0]
+	for _, interval := range intervals {
+		require.Equal(t, next, interval.Min, "root [%d, %d] does not start right after the previous one", interval.Min, interval.Max)
+		require.LessOrEqual(t, interval.Max-interval.Min+1, uint64(cap), "root [%d, %d] exceeds the cap of %d messages", interval.Min, interval.Max, cap)
+		next = interval.Max + 1
+	}
+	require.Equal(t, seqNums[len(seqNums)-1], next-1, "roots do not span the whole backlog")
+	for i, seqNum := range seqNums {
+		require.Equal(t, seqNums[0]+uint64(i), seqNum, "backlog is not contiguous")
+	}
+}
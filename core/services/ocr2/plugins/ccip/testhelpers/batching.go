@@ -0,0 +1,75 @@
+package testhelpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/merklemulti"
+)
+
+// EstimateMessageExecutionGas returns the gas the exec plugin budgets for executing msg: its gas limit plus
+// the overhead for calldata, execution state, token transfers and the receiver call.
+// It mirrors overheadGas of the exec plugin, which cannot be imported from here.
+func EstimateMessageExecutionGas(msg evm_2_evm_offramp.InternalEVM2EVMMessage) uint64 {
+	numTokens := uint64(len(msg.TokenAmounts))
+	messageBytes := 10*32 + // constant message part
+		(20+32)*numTokens + // token address and amount
+		uint64(len(msg.Data))
+	overhead := 16*messageBytes + // calldata
+		2_100 + 20_000 + 100 + // execution state
+		2_600 + 30_000*3 // receiver call and its ERC165 checks
+	if numTokens > 0 {
+		overhead += (2_100*5+5_000+20_000)*numTokens + // token transfers
+			2_100 + 5_000 // rate limiter
+	}
+	return msg.GasLimit.Uint64() + overhead
+}
+
+// BuildOversizedBatch partitions msgs, in order, into sub-batches whose estimated execution gas each fits under gasCeil.
+// It fails the test if a single message does not fit on its own.
+func BuildOversizedBatch(t *testing.T, msgs []evm_2_evm_offramp.InternalEVM2EVMMessage, gasCeil uint64) [][]evm_2_evm_offramp.InternalEVM2EVMMessage {
+	var batches [][]evm_2_evm_offramp.InternalEVM2EVMMessage
+	var batch []evm_2_evm_offramp.InternalEVM2EVMMessage
+	var batchGas uint64
+	for _, msg := range msgs {
+		msgGas := EstimateMessageExecutionGas(msg)
+		require.LessOrEqual(t, msgGas, gasCeil, "message %d does not fit in a block on its own", msg.SequenceNumber)
+		if batchGas+msgGas > gasCeil {
+			batches = append(batches, batch)
+			batch, batchGas = nil, 0
+		}
+		batch = append(batch, msg)
+		batchGas += msgGas
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// AssertBatchesExecute transmits each batch in its own execution report and block, and asserts that every
+// transmission stays under gasCeil and that all of the messages are executed successfully.
+// leafMsgs are all the messages committed under tree, in sequence number order.
+func (c *CCIPContracts) AssertBatchesExecute(t *testing.T, oracle SimulatedOracle, tree *merklemulti.Tree[[32]byte], leafMsgs []evm_2_evm_offramp.InternalEVM2EVMMessage, batches [][]evm_2_evm_offramp.InternalEVM2EVMMessage, gasCeil uint64) {
+	require.NotEmpty(t, leafMsgs)
+	blocks := make(map[uint64]bool, len(batches))
+	for _, batch := range batches {
+		indices := make([]int, len(batch))
+		for i, msg := range batch {
+			indices[i] = int(msg.SequenceNumber - leafMsgs[0].SequenceNumber)
+		}
+		rec, err := c.TransmitExecutionReport(t, oracle, BuildExecutionReport(t, tree, leafMsgs, indices))
+		require.NoError(t, err)
+		require.LessOrEqual(t, rec.GasUsed, gasCeil)
+		require.False(t, blocks[rec.BlockNumber.Uint64()], "batches were executed in the same block")
+		blocks[rec.BlockNumber.Uint64()] = true
+	}
+	for _, batch := range batches {
+		for _, msg := range batch {
+			c.AssertExecStateForSeqNum(t, msg.SequenceNumber, abihelpers.ExecutionStateSuccess)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOversizedBatchExecutesAcrossBlocks(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+
+	// Each message uses the maximum gas limit of the onRamp, so that together they exceed the block gas limit.
+	c.SendDataMessages(t, 8, big.NewInt(4_000_000))
+
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 8)
+	var totalGas uint64
+	for _, msg := range msgs {
+		totalGas += EstimateMessageExecutionGas(msg)
+	}
+	gasCeil := ethconfig.Defaults.Miner.GasCeil
+	require.Greater(t, totalGas, gasCeil)
+
+	tree := c.CommitMessages(t, msgs)
+	batches := BuildOversizedBatch(t, msgs, gasCeil)
+	require.Greater(t, len(batches), 1)
+	c.AssertBatchesExecute(t, oracles[0], tree, msgs, batches, gasCeil)
+}
@@ -0,0 +1,41 @@
+package testhelpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/stretchr/testify/require"
+)
+
+// RunLaneWithBlockTimes runs the lane from start for duration, in which the source chain mines a block every
+// srcInterval and the dest chain every destInterval, stamped at those times. When both chains are due at the same
+// time, the source chain mines first. The time of every block is handed to the round of its chain, srcRound or
+// destRound, whose transactions land in the block. Blocks mined by a round beyond that follow at the simulated ten
+// second step, so the interval of a chain must leave room for them before its next block.
+func (c *CCIPContracts) RunLaneWithBlockTimes(t *testing.T, srcInterval, destInterval time.Duration, start time.Time, duration time.Duration, srcRound, destRound func(t *testing.T, now time.Time)) {
+	require.Greater(t, srcInterval, simulatedBlockInterval*time.Second)
+	require.Greater(t, destInterval, simulatedBlockInterval*time.Second)
+	for srcNext, destNext := srcInterval, destInterval; srcNext <= duration || destNext <= duration; {
+		if srcNext <= destNext {
+			runRoundAt(t, c.Source.Chain, start.Add(srcNext), srcInterval, srcRound)
+			srcNext += srcInterval
+			continue
+		}
+		runRoundAt(t, c.Dest.Chain, start.Add(destNext), destInterval, destRound)
+		destNext += destInterval
+	}
+}
+
+// runRoundAt runs round in a block of chain at now, and asserts that the blocks it mined end before the next block
+// of chain, which is due interval after it. Transactions cannot be sent into a block with an adjusted time, so an
+// empty block is mined one step before now, and the block after it, which is mined at now, is left to round.
+func runRoundAt(t *testing.T, chain *backends.SimulatedBackend, now time.Time, interval time.Duration, round func(t *testing.T, now time.Time)) {
+	SetChainClock(t, chain, now.Add(-simulatedBlockInterval*time.Second))
+	round(t, now)
+	if ChainClock(chain).Before(now) {
+		chain.Commit()
+	}
+	require.True(t, ChainClock(chain).Before(now.Add(interval-simulatedBlockInterval*time.Second)),
+		"round at %s overran the block interval of %s", now, interval)
+}
@@ -0,0 +1,365 @@
+package testhelpers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/lock_release_token_pool"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/generated/link_token_interface"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// blocklistableTokenABI covers the functions of a blocklistable token beyond ERC20.
+const blocklistableTokenABI = `[
+	{"type":"function","name":"blocklist","inputs":[{"name":"account","type":"address"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"isBlocklisted","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"view"}
+]`
+
+// blocklistableTokenOwnerSlot is the storage slot of the owner of a blocklistable token, which no balance can be
+// stored at, as balances are stored at the slots of 20 byte addresses.
+const blocklistableTokenOwnerSlot = "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+
+// blocklistableTokenRuntime is the assembly of a minimal ERC20 laid out like a fee-on-transfer token, with whether
+// an account is blocklisted at keccak256(account).
+// %[1]s is the blocklist selector, %[2]s the isBlocklisted selector and %[3]s the owner slot.
+const blocklistableTokenRuntime = `
+PUSH 0
+CALLDATALOAD
+PUSH 224
+SHR
+DUP1
+PUSH 0x70a08231
+EQ
+JUMPI @balanceOf
+DUP1
+PUSH 0xdd62ed3e
+EQ
+JUMPI @allowance
+DUP1
+PUSH 0x095ea7b3
+EQ
+JUMPI @approve
+DUP1
+PUSH 0xa9059cbb
+EQ
+JUMPI @transfer
+DUP1
+PUSH 0x23b872dd
+EQ
+JUMPI @transferFrom
+DUP1
+PUSH 0x313ce567
+EQ
+JUMPI @decimals
+DUP1
+PUSH %[1]s
+EQ
+JUMPI @blocklist
+DUP1
+PUSH %[2]s
+EQ
+JUMPI @isBlocklisted
+JUMP @fail
+
+balanceOf:
+PUSH 4
+CALLDATALOAD
+SLOAD
+JUMP @returnWord
+
+allowance:
+PUSH 4
+CALLDATALOAD
+PUSH 0
+MSTORE
+PUSH 36
+CALLDATALOAD
+PUSH 32
+MSTORE
+PUSH 64
+PUSH 0
+KECCAK256
+SLOAD
+JUMP @returnWord
+
+approve:
+CALLER
+PUSH 0
+MSTORE
+PUSH 4
+CALLDATALOAD
+PUSH 32
+MSTORE
+PUSH 36
+CALLDATALOAD
+PUSH 64
+PUSH 0
+KECCAK256
+SSTORE
+JUMP @returnTrue
+
+transfer:
+CALLER
+PUSH 4
+CALLDATALOAD
+PUSH 36
+CALLDATALOAD
+JUMP @move
+
+transferFrom:
+PUSH 4
+CALLDATALOAD
+PUSH 0
+MSTORE
+CALLER
+PUSH 32
+MSTORE
+PUSH 64
+PUSH 0
+KECCAK256
+DUP1
+SLOAD
+PUSH 68
+CALLDATALOAD
+DUP1
+DUP3
+LT
+JUMPI @fail
+SWAP1
+SUB
+SWAP1
+SSTORE
+PUSH 4
+CALLDATALOAD
+PUSH 36
+CALLDATALOAD
+PUSH 68
+CALLDATALOAD
+JUMP @move
+
+decimals:
+PUSH 18
+JUMP @returnWord
+
+blocklist:
+PUSH %[3]s
+SLOAD
+CALLER
+EQ
+ISZERO
+JUMPI @fail
+PUSH 1
+PUSH 4
+CALLDATALOAD
+PUSH 0
+MSTORE
+PUSH 32
+PUSH 0
+KECCAK256
+SSTORE
+STOP
+
+isBlocklisted:
+PUSH 4
+CALLDATALOAD
+PUSH 0
+MSTORE
+PUSH 32
+PUSH 0
+KECCAK256
+SLOAD
+JUMP @returnWord
+
+;; stack: from, to, amount
+move:
+DUP2
+PUSH 0
+MSTORE
+PUSH 32
+PUSH 0
+KECCAK256
+SLOAD
+JUMPI @fail
+DUP3
+PUSH 0
+MSTORE
+PUSH 32
+PUSH 0
+KECCAK256
+SLOAD
+JUMPI @fail
+DUP3
+SLOAD
+DUP1
+DUP3
+GT
+JUMPI @fail
+DUP2
+SWAP1
+SUB
+DUP4
+SSTORE
+DUP2
+SLOAD
+ADD
+SWAP1
+SSTORE
+POP
+JUMP @returnTrue
+
+returnTrue:
+PUSH 1
+JUMP @returnWord
+
+returnWord:
+PUSH 0
+MSTORE
+PUSH 32
+PUSH 0
+RETURN
+
+fail:
+PUSH 0
+DUP1
+REVERT
+`
+
+// BlocklistableToken is an ERC20 whose owner can blocklist accounts, like regulated stablecoins do. Transfers
+// from or to a blocklisted account revert.
+type BlocklistableToken struct {
+	*link_token_interface.LinkToken
+	contract *bind.BoundContract
+}
+
+// Blocklist blocklists account, which only the owner of the token may do.
+func (tok *BlocklistableToken) Blocklist(opts *bind.TransactOpts, account common.Address) (*types.Transaction, error) {
+	return tok.contract.Transact(opts, "blocklist", account)
+}
+
+// IsBlocklisted returns whether account is blocklisted.
+func (tok *BlocklistableToken) IsBlocklisted(opts *bind.CallOpts, account common.Address) (bool, error) {
+	var out []interface{}
+	if err := tok.contract.Call(opts, &out, "isBlocklisted", account); err != nil {
+		return false, err
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
+// blocklistableTokenCode returns the creation code of a blocklistable token owned by the deployer, which mints
+// it the same supply as a fee-on-transfer token.
+func blocklistableTokenCode(t *testing.T, tokenABI abi.ABI) []byte {
+	runtime := assemble(t, fmt.Sprintf(blocklistableTokenRuntime,
+		hexutil.Encode(tokenABI.Methods["blocklist"].ID), hexutil.Encode(tokenABI.Methods["isBlocklisted"].ID), blocklistableTokenOwnerSlot))
+
+	initCode := []byte{0x7f} // PUSH32 supply
+	initCode = append(initCode, common.LeftPadBytes(feeOnTransferTokenSupply.Bytes(), 32)...)
+	initCode = append(initCode, 0x33, 0x55) // SSTORE(CALLER, supply)
+	initCode = append(initCode, 0x33, 0x7f) // CALLER, PUSH32 owner slot
+	initCode = append(initCode, common.FromHex(blocklistableTokenOwnerSlot)...)
+	initCode = append(initCode, 0x55) // SSTORE(owner slot, CALLER)
+	initCode = append(initCode,
+		0x61, byte(len(runtime)>>8), byte(len(runtime)), 0x80, // PUSH2 len, DUP1
+		0x60, byte(len(initCode)+12), 0x60, 0x00, 0x39, // CODECOPY(0, offset, len)
+		0x60, 0x00, 0xf3, // RETURN(0, len)
+	)
+	return append(initCode, runtime...)
+}
+
+// DeployBlocklistableToken deploys a blocklistable token owned by owner, to whom the whole supply is minted.
+func DeployBlocklistableToken(t *testing.T, chain *backends.SimulatedBackend, owner *bind.TransactOpts) (*BlocklistableToken, common.Address) {
+	tokenABI, err := abi.JSON(strings.NewReader(blocklistableTokenABI))
+	require.NoError(t, err)
+	address, tx, contract, err := bind.DeployContract(owner, tokenABI, blocklistableTokenCode(t, tokenABI), chain)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+	code, err := chain.CodeAt(context.Background(), address, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, code)
+	linkToken, err := link_token_interface.NewLinkToken(address, chain)
+	require.NoError(t, err)
+	return &BlocklistableToken{LinkToken: linkToken, contract: contract}, address
+}
+
+// ReleaseSourceLinkAs makes the offRamp release destToken, which the dest user must hold, for source LINK. A new
+// lock release pool of destToken is deployed on the dest chain with a liquidity of HundredLink, which replaces the
+// dest LINK pool for source LINK and is returned.
+func (c *CCIPContracts) ReleaseSourceLinkAs(t *testing.T, destToken common.Address) *lock_release_token_pool.LockReleaseTokenPool {
+	poolAddress, tx, _, err := lock_release_token_pool.DeployLockReleaseTokenPool(c.Dest.User, c.Dest.Chain, destToken, []common.Address{}, c.Dest.ARMProxy.Address(), true)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	pool, err := lock_release_token_pool.NewLockReleaseTokenPool(poolAddress, c.Dest.Chain)
+	require.NoError(t, err)
+	token, err := link_token_interface.NewLinkToken(destToken, c.Dest.Chain)
+	require.NoError(t, err)
+
+	var txs []*types.Transaction
+	rateLimiterConfig := lock_release_token_pool.RateLimiterConfig{IsEnabled: true, Capacity: HundredLink, Rate: big.NewInt(1e18)}
+	tx, err = pool.ApplyRampUpdates(c.Dest.User, nil, []lock_release_token_pool.TokenPoolRampUpdate{{Ramp: c.Dest.OffRamp.Address(), Allowed: true, RateLimiterConfig: rateLimiterConfig}})
+	require.NoError(t, err)
+	txs = append(txs, tx)
+	tx, err = token.Approve(c.Dest.User, poolAddress, HundredLink)
+	require.NoError(t, err)
+	txs = append(txs, tx)
+	ConfirmTxs(t, txs, c.Dest.Chain)
+
+	txs = txs[:0]
+	tx, err = pool.AddLiquidity(c.Dest.User, HundredLink)
+	require.NoError(t, err)
+	txs = append(txs, tx)
+	sourceLink := c.Source.LinkToken.Address()
+	tx, err = c.Dest.OffRamp.ApplyPoolUpdates(c.Dest.User,
+		[]evm_2_evm_offramp.InternalPoolUpdate{{Token: sourceLink, Pool: c.Dest.Pool.Address()}},
+		[]evm_2_evm_offramp.InternalPoolUpdate{{Token: sourceLink, Pool: poolAddress}},
+	)
+	require.NoError(t, err)
+	txs = append(txs, tx)
+	tx, err = c.Dest.PriceRegistry.UpdatePrices(c.Dest.User, tokenPriceUpdate(destToken, big.NewInt(1e18)))
+	require.NoError(t, err)
+	txs = append(txs, tx)
+	ConfirmTxs(t, txs, c.Dest.Chain)
+	return pool
+}
+
+// AssertBlocklistedRecipientFails asserts that the execution in receipt marked the message with seqNum
+// blocklisted, whose recipient is blocklisted by the token it transfers, as FAILURE with the revert of the
+// release wrapped in a TokenHandlingError, while every message with one of the others sequence numbers
+// executed in the same batch succeeded.
+func (c *CCIPContracts) AssertBlocklistedRecipientFails(t *testing.T, receipt *types.Receipt, blocklisted uint64, others []uint64) {
+	offRampABI, err := evm_2_evm_offramp.EVM2EVMOffRampMetaData.GetAbi()
+	require.NoError(t, err)
+	states := make(map[uint64]*evm_2_evm_offramp.EVM2EVMOffRampExecutionStateChanged)
+	for _, log := range receipt.Logs {
+		if log.Address != c.Dest.OffRamp.Address() {
+			continue
+		}
+		if stateChanged, err2 := c.Dest.OffRamp.ParseExecutionStateChanged(*log); err2 == nil {
+			states[stateChanged.SequenceNumber] = stateChanged
+		}
+	}
+
+	failed, ok := states[blocklisted]
+	require.True(t, ok, "seqNum %d was not executed in the batch", blocklisted)
+	require.Equal(t, abihelpers.ExecutionStateFailure, abihelpers.MessageExecutionState(failed.State))
+	require.GreaterOrEqual(t, len(failed.ReturnData), 4, "seqNum %d failed without revert data", blocklisted)
+	require.Equal(t, hexutil.Encode(offRampABI.Errors["TokenHandlingError"].ID.Bytes()[:4]), hexutil.Encode(failed.ReturnData[:4]),
+		"seqNum %d did not fail releasing its tokens", blocklisted)
+	c.AssertExecStateForSeqNum(t, blocklisted, abihelpers.ExecutionStateFailure)
+	for _, seqNum := range others {
+		succeeded, ok := states[seqNum]
+		require.True(t, ok, "seqNum %d was not executed in the batch", seqNum)
+		require.Equal(t, abihelpers.ExecutionStateSuccess, abihelpers.MessageExecutionState(succeeded.State), "seqNum %d did not succeed", seqNum)
+		c.AssertExecStateForSeqNum(t, seqNum, abihelpers.ExecutionStateSuccess)
+	}
+}
@@ -0,0 +1,60 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+func TestBlocklistedRecipient(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	destToken, destTokenAddress := DeployBlocklistableToken(t, c.Dest.Chain, c.Dest.User)
+	pool := c.ReleaseSourceLinkAs(t, destTokenAddress)
+	blocked := common.HexToAddress("0x4444444444444444444444444444444444444444")
+	allowed := common.HexToAddress("0x5555555555555555555555555555555555555555")
+
+	// Only the owner can blocklist, after which the account cannot receive the token.
+	_, err := destToken.Blocklist(NewFundedUser(t, c.Dest.Chain, c.Dest.User), blocked)
+	require.Error(t, err)
+	tx, err := destToken.Blocklist(c.Dest.User, blocked)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	isBlocklisted, err := destToken.IsBlocklisted(nil, blocked)
+	require.NoError(t, err)
+	require.True(t, isBlocklisted)
+	_, err = destToken.Transfer(c.Dest.User, blocked, big.NewInt(1))
+	require.Error(t, err)
+
+	amount := big.NewInt(1e18)
+	tx, err = c.Source.LinkToken.Approve(c.Source.User, c.Source.Router.Address(), HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	for _, receiver := range []common.Address{allowed, blocked, allowed} {
+		c.SendRequest(t, router.ClientEVM2AnyMessage{
+			Receiver:     MustEncodeAddress(t, receiver),
+			Data:         []byte{},
+			TokenAmounts: []router.ClientEVMTokenAmount{{Token: c.Source.LinkToken.Address(), Amount: amount}},
+			FeeToken:     c.Source.LinkToken.Address(),
+			ExtraArgs:    extraArgs,
+		})
+	}
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 3)
+	tree := c.CommitMessages(t, msgs)
+
+	receipt, err := c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0, 1, 2}))
+	require.NoError(t, err)
+	c.AssertBlocklistedRecipientFails(t, receipt, msgs[1].SequenceNumber, []uint64{msgs[0].SequenceNumber, msgs[2].SequenceNumber})
+	require.Zero(t, GetBalance(t, c.Dest.Chain, destTokenAddress, blocked).Sign())
+	require.Equal(t, new(big.Int).Mul(amount, big.NewInt(2)).String(), GetBalance(t, c.Dest.Chain, destTokenAddress, allowed).String())
+	require.Equal(t, new(big.Int).Sub(HundredLink, new(big.Int).Mul(amount, big.NewInt(2))).String(), GetBalance(t, c.Dest.Chain, destTokenAddress, pool.Address()).String())
+}
@@ -0,0 +1,129 @@
+package testhelpers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/maybe_revert_message_receiver"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// receiverSupportsInterface is the assembly of the ERC165 supportsInterface of test receivers, which jump to it
+// with the selector of the call on the stack. %[1]s is the ccipReceive selector, the receiver interface ID.
+const receiverSupportsInterface = `
+supportsInterface:
+PUSH 4
+CALLDATALOAD
+PUSH 224
+SHR
+DUP1
+PUSH 0x01ffc9a7
+EQ
+JUMPI @supported
+PUSH %[1]s
+EQ
+JUMPI @supported
+PUSH 0
+JUMP @returnWord
+
+supported:
+PUSH 1
+JUMP @returnWord
+
+returnWord:
+PUSH 0
+MSTORE
+PUSH 32
+PUSH 0
+RETURN
+`
+
+// chattyReceiverRuntime is the assembly of a receiver whose ccipReceive returns the payload appended to its code.
+// %[1]s is the ccipReceive selector and %[2]d the payload length.
+const chattyReceiverRuntime = `
+PUSH 0
+CALLDATALOAD
+PUSH 224
+SHR
+DUP1
+PUSH 0x01ffc9a7
+EQ
+JUMPI @supportsInterface
+PUSH %[1]s
+EQ
+JUMPI @ccipReceive
+PUSH 0
+DUP1
+REVERT
+
+ccipReceive:
+PUSH %[2]d
+DUP1
+CODESIZE
+SUB
+PUSH 0
+CODECOPY
+PUSH %[2]d
+PUSH 0
+RETURN
+` + receiverSupportsInterface
+
+// ccipReceiveSelector returns the hex encoded selector of ccipReceive, which is also the receiver interface ID.
+func ccipReceiveSelector(t *testing.T) string {
+	receiverABI, err := maybe_revert_message_receiver.MaybeRevertMessageReceiverMetaData.GetAbi()
+	require.NoError(t, err)
+	return hexutil.Encode(receiverABI.Methods["ccipReceive"].ID)
+}
+
+// deployReceiver deploys a receiver with the given runtime code.
+func deployReceiver(t *testing.T, chain *backends.SimulatedBackend, owner *bind.TransactOpts, runtime []byte) common.Address {
+	address, tx, _, err := bind.DeployContract(owner, abi.ABI{}, creationCode(runtime), chain)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+	code, err := chain.CodeAt(context.Background(), address, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, code)
+	return address
+}
+
+// DeployChattyReceiver deploys a message receiver that accepts every message and returns payload from ccipReceive,
+// which receivers are not expected to return anything from.
+func DeployChattyReceiver(t *testing.T, chain *backends.SimulatedBackend, owner *bind.TransactOpts, payload []byte) common.Address {
+	runtime := append(assemble(t, fmt.Sprintf(chattyReceiverRuntime, ccipReceiveSelector(t), len(payload))), payload...)
+	return deployReceiver(t, chain, owner, runtime)
+}
+
+// AssertExecutedIgnoringReturnData asserts that the execution in receipt routed the message with seqNum to its
+// receiver and marked it as successful, without surfacing anything the receiver returned.
+func (c *CCIPContracts) AssertExecutedIgnoringReturnData(t *testing.T, receipt *types.Receipt, seqNum uint64) {
+	var routed, executed bool
+	for _, log := range receipt.Logs {
+		if log.Address == c.Dest.Router.Address() {
+			_, err := c.Dest.Router.ParseMessageExecuted(*log)
+			require.NoError(t, err)
+			routed = true
+		}
+		if log.Address != c.Dest.OffRamp.Address() {
+			continue
+		}
+		stateChanged, err := c.Dest.OffRamp.ParseExecutionStateChanged(*log)
+		if err != nil || stateChanged.SequenceNumber != seqNum {
+			continue
+		}
+		require.Equal(t, abihelpers.ExecutionStateSuccess, abihelpers.MessageExecutionState(stateChanged.State))
+		require.Empty(t, stateChanged.ReturnData)
+		executed = true
+	}
+	require.True(t, routed, "message was not routed to its receiver")
+	require.True(t, executed, "no execution of seqNum %d in receipt", seqNum)
+	c.AssertExecStateForSeqNum(t, seqNum, abihelpers.ExecutionStateSuccess)
+}
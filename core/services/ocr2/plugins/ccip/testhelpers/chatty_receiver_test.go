@@ -0,0 +1,65 @@
+package testhelpers
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/maybe_revert_message_receiver"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+func TestChattyReceiver(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	offRampABI, err := evm_2_evm_offramp.EVM2EVMOffRampMetaData.GetAbi()
+	require.NoError(t, err)
+	receiverABI, err := maybe_revert_message_receiver.MaybeRevertMessageReceiverMetaData.GetAbi()
+	require.NoError(t, err)
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	tx, err := c.Source.LinkToken.Approve(c.Source.User, c.Source.Router.Address(), HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+
+	for _, tc := range []struct {
+		name    string
+		payload []byte
+	}{
+		{"empty", []byte{}},
+		{"false", make([]byte, 32)},
+		{"text", []byte("unexpected")},
+		{"error selector", offRampABI.Errors["ReceiverError"].ID.Bytes()[:4]},
+		{"oversized", bytes.Repeat([]byte{0xff}, 4096)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			receiver := DeployChattyReceiver(t, c.Dest.Chain, c.Dest.User, tc.payload)
+			returned, err := c.Dest.Chain.CallContract(context.Background(), ethereum.CallMsg{To: &receiver, Data: receiverABI.Methods["ccipReceive"].ID}, nil)
+			require.NoError(t, err)
+			require.Equal(t, hexutil.Encode(tc.payload), hexutil.Encode(returned))
+
+			startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+			c.SendRequest(t, router.ClientEVM2AnyMessage{
+				Receiver:     MustEncodeAddress(t, receiver),
+				Data:         []byte("hello"),
+				TokenAmounts: []router.ClientEVMTokenAmount{},
+				FeeToken:     c.Source.LinkToken.Address(),
+				ExtraArgs:    extraArgs,
+			})
+			msgs := c.SendRequestedMessages(t, startBlock)
+			require.Len(t, msgs, 1)
+
+			tree := c.CommitMessages(t, msgs)
+			receipt, err := c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0}))
+			require.NoError(t, err)
+			c.AssertExecutedIgnoringReturnData(t, receipt, msgs[0].SequenceNumber)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package testhelpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/stretchr/testify/require"
+)
+
+// simulatedBlockInterval is the number of seconds a simulated backend puts between consecutive blocks.
+const simulatedBlockInterval = 10
+
+// ChainClock returns the timestamp of the head of chain, which is what block.timestamp evaluates to in calls.
+func ChainClock(chain *backends.SimulatedBackend) time.Time {
+	return time.Unix(int64(chain.Blockchain().CurrentBlock().Time), 0)
+}
+
+// SetChainClock mines a block on chain with timestamp t0. Simulated backends keep their own clock, which
+// continues from t0 as blocks are mined, independent of wall clock time and other chains. The clock of a
+// chain cannot go backwards, so t0 must be after the current head, and there must be no pending transactions.
+func SetChainClock(t *testing.T, chain *backends.SimulatedBackend, t0 time.Time) {
+	head := chain.Blockchain().CurrentBlock().Time
+	require.Greater(t, t0.Unix(), int64(head), "chain clock cannot go backwards")
+	offset := t0.Unix() - int64(head) - simulatedBlockInterval
+	require.NoError(t, chain.AdjustTime(time.Duration(offset)*time.Second))
+	chain.Commit()
+	require.Equal(t, t0.Unix(), ChainClock(chain).Unix())
+}
+
+// AdvanceChainClock mines a block on chain d after its current head.
+func AdvanceChainClock(t *testing.T, chain *backends.SimulatedBackend, d time.Duration) {
+	SetChainClock(t, chain, ChainClock(chain).Add(d))
+}
@@ -0,0 +1,60 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/commit_store"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/price_registry"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestClockSkewPriceStaleness(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	c.SetupSimulatedOracles(t)
+	base := ChainClock(c.Source.Chain)
+	if destClock := ChainClock(c.Dest.Chain); destClock.After(base) {
+		base = destClock
+	}
+	base = base.Add(time.Minute)
+	SetChainClock(t, c.Dest.Chain, base)
+	SetChainClock(t, c.Source.Chain, base.Add(time.Hour))
+	require.Equal(t, time.Hour, ChainClock(c.Source.Chain).Sub(ChainClock(c.Dest.Chain)))
+
+	// Prices committed to the dest chain are timestamped by the dest clock.
+	token := c.Dest.LinkToken.Address()
+	report, err := abihelpers.EncodeCommitReport(commit_store.CommitStoreCommitReport{
+		PriceUpdates: commit_store.InternalPriceUpdates{
+			TokenPriceUpdates: []commit_store.InternalTokenPriceUpdate{{SourceToken: token, UsdPerToken: big.NewInt(8e18)}},
+			UsdPerUnitGas:     big.NewInt(0),
+		},
+	})
+	require.NoError(t, err)
+	tx, err := c.Dest.CommitStoreHelper.Report(c.Dest.User, report, big.NewInt(1))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	price, err := c.Dest.PriceRegistry.GetTokenPrice(nil, token)
+	require.NoError(t, err)
+	require.Equal(t, ChainClock(c.Dest.Chain).Unix(), int64(price.Timestamp))
+
+	threshold, err := c.Dest.PriceRegistry.GetStalenessThreshold(nil)
+	require.NoError(t, err)
+	stalenessThreshold := time.Duration(threshold.Int64()) * time.Second
+
+	// Advancing the source clock past the staleness threshold does not affect staleness on the dest chain.
+	AdvanceChainClock(t, c.Source.Chain, 2*stalenessThreshold)
+	_, err = c.Dest.PriceRegistry.ConvertTokenAmount(nil, token, big.NewInt(1), token)
+	require.NoError(t, err)
+
+	// The price becomes stale once the dest clock passes the threshold.
+	AdvanceChainClock(t, c.Dest.Chain, stalenessThreshold-time.Minute)
+	_, err = c.Dest.PriceRegistry.ConvertTokenAmount(nil, token, big.NewInt(1), token)
+	require.NoError(t, err)
+	AdvanceChainClock(t, c.Dest.Chain, 2*time.Minute)
+	_, err = c.Dest.PriceRegistry.ConvertTokenAmount(nil, token, big.NewInt(1), token)
+	AssertRevertedWith(t, err, price_registry.PriceRegistryABI, "StaleTokenPrice")
+}
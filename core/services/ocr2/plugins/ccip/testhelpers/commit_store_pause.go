@@ -0,0 +1,53 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/commit_store"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+)
+
+// PauseCommitStore pauses commitStore as its owner and asserts that it reports being paused.
+func PauseCommitStore(t *testing.T, chain *backends.SimulatedBackend, commitStore *commit_store.CommitStore, owner *bind.TransactOpts) {
+	tx, err := commitStore.Pause(owner)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+	paused, err := commitStore.Paused(nil)
+	require.NoError(t, err)
+	require.True(t, paused)
+}
+
+// UnpauseCommitStore unpauses commitStore as its owner and asserts that it no longer reports being paused.
+func UnpauseCommitStore(t *testing.T, chain *backends.SimulatedBackend, commitStore *commit_store.CommitStore, owner *bind.TransactOpts) {
+	tx, err := commitStore.Unpause(owner)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+	paused, err := commitStore.Paused(nil)
+	require.NoError(t, err)
+	require.False(t, paused)
+}
+
+// PostCommitExpectingPaused posts a commit of msgs to the paused dest commitStore and asserts that it is
+// rejected with PausedError, leaving the next expected sequence number unchanged.
+func (c *CCIPContracts) PostCommitExpectingPaused(t *testing.T, msgs []evm_2_evm_offramp.InternalEVM2EVMMessage) {
+	paused, err := c.Dest.CommitStore.Paused(nil)
+	require.NoError(t, err)
+	require.True(t, paused, "commitStore is not paused")
+	nextSeqNum, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+
+	report, _ := encodeMessagesCommitReport(t, msgs)
+	_, err = c.Dest.CommitStoreHelper.Report(c.Dest.User, report, big.NewInt(1))
+	AssertRevertedWith(t, err, commit_store.CommitStoreABI, "PausedError")
+
+	c.Dest.Chain.Commit()
+	after, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, nextSeqNum, after)
+}
@@ -0,0 +1,29 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitStorePause(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	c.SetupSimulatedOracles(t)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+	c.SendDataMessages(t, 2, big.NewInt(200_000))
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 2)
+
+	PauseCommitStore(t, c.Dest.Chain, c.Dest.CommitStore, c.Dest.User)
+	c.PostCommitExpectingPaused(t, msgs)
+
+	UnpauseCommitStore(t, c.Dest.Chain, c.Dest.CommitStore, c.Dest.User)
+	tree := c.CommitMessages(t, msgs)
+	timestamp, err := c.Dest.CommitStore.GetMerkleRoot(nil, tree.Root())
+	require.NoError(t, err)
+	require.NotZero(t, timestamp.Uint64())
+	nextSeqNum, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, msgs[1].SequenceNumber+1, nextSeqNum)
+}
@@ -0,0 +1,47 @@
+package testhelpers
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/smartcontractkit/libocr/offchainreporting2plus/chains/evmutil"
+	ocr2types "github.com/smartcontractkit/libocr/offchainreporting2plus/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/commit_store"
+)
+
+// TransmitCommitReport transmits report from transmitter to the dest commitStore in round 1 of epoch 1, signed by
+// the commit DON made up of oracles, as set up by SetupSimulatedOracles. Errors returned by the commitStore are
+// passed to the caller, and a returned transaction is not yet mined.
+func (c *CCIPContracts) TransmitCommitReport(t *testing.T, oracles []SimulatedOracle, transmitter *bind.TransactOpts, report []byte) (*types.Transaction, error) {
+	configDetails, err := c.Dest.CommitStore.LatestConfigDetails(nil)
+	require.NoError(t, err)
+	reportCtx := ocr2types.ReportContext{ReportTimestamp: ocr2types.ReportTimestamp{
+		ConfigDigest: configDetails.ConfigDigest,
+		Epoch:        1,
+		Round:        1,
+	}}
+	rs, ss, vs := c.signCommitReport(t, oracles, reportCtx, report)
+	return c.Dest.CommitStore.Transmit(transmitter, evmutil.RawReportContext(reportCtx), report, rs, ss, vs)
+}
+
+// PostCommitFromUnauthorizedExpectingReject transmits report, properly signed by the commit DON made up of oracles,
+// from attacker, which must not be a transmitter of the DON, and asserts that the commitStore rejects it with
+// UnauthorizedTransmitter, leaving the next expected sequence number unchanged.
+func (c *CCIPContracts) PostCommitFromUnauthorizedExpectingReject(t *testing.T, oracles []SimulatedOracle, attacker *bind.TransactOpts, report []byte) {
+	transmitters, err := c.Dest.CommitStore.GetTransmitters(nil)
+	require.NoError(t, err)
+	require.NotContains(t, transmitters, attacker.From, "attacker is a configured transmitter")
+	nextSeqNum, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+
+	_, err = c.TransmitCommitReport(t, oracles, attacker, report)
+	AssertRevertedWith(t, err, commit_store.CommitStoreABI, "UnauthorizedTransmitter")
+
+	c.Dest.Chain.Commit()
+	after, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, nextSeqNum, after)
+}
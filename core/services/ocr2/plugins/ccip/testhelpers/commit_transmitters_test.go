@@ -0,0 +1,30 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostCommitFromUnauthorizedExpectingReject(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	c.SendDataMessages(t, 2, big.NewInt(100_000))
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 2)
+	report, _ := encodeMessagesCommitReport(t, msgs)
+
+	// Neither an unknown key nor the owner of the commitStore may transmit.
+	c.PostCommitFromUnauthorizedExpectingReject(t, oracles, NewFundedUser(t, c.Dest.Chain, c.Dest.User), report)
+	c.PostCommitFromUnauthorizedExpectingReject(t, oracles, c.Dest.User, report)
+
+	tx, err := c.TransmitCommitReport(t, oracles, oracles[0].Transmitter, report)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	next, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, msgs[1].SequenceNumber+1, next)
+}
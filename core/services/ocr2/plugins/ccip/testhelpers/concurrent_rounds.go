@@ -0,0 +1,166 @@
+package testhelpers
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// concurrentRoundRetryInterval is the pause of the executor between attempts to execute a message whose root
+// is not committed yet.
+const concurrentRoundRetryInterval = 10 * time.Millisecond
+
+// concurrentRound is a commit round of DriveConcurrentRounds along with the messages it covers.
+type concurrentRound struct {
+	report []byte
+	msgs   []evm_2_evm_offramp.InternalEVM2EVMMessage
+	// execReports holds the encoded single message execution report of each message of the round.
+	execReports [][]byte
+}
+
+// DriveConcurrentRounds sends messages data messages and commits and executes them from two goroutines,
+// which make progress concurrently on the dest chain. Roots cover one to three messages each. The executor
+// attempts the messages in order as soon as they are sent, and each root is only committed once the execution
+// of its first message has been rejected, so that every round sees executions both before and after its root
+// is committed. DriveConcurrentRounds asserts that every attempt before the root of the message is committed
+// is rejected with RootNotCommitted, that no execution is included on chain ahead of its commit and that all
+// messages end up executed successfully. The lane is configured with simulated oracles, the first of which
+// transmits the executions, while the commit reports are posted by the dest user through the commitStore helper.
+func (c *CCIPContracts) DriveConcurrentRounds(t *testing.T, messages int) {
+	require.Positive(t, messages)
+	oracle := c.SetupSimulatedOracles(t)[0]
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	c.SendDataMessages(t, messages, big.NewInt(100_000))
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, messages)
+
+	var rounds []concurrentRound
+	for start, size := 0, 1; start < len(msgs); start, size = start+size, size%3+1 {
+		end := start + size
+		if end > len(msgs) {
+			end = len(msgs)
+		}
+		report, tree := encodeMessagesCommitReport(t, msgs[start:end])
+		round := concurrentRound{report: report, msgs: msgs[start:end]}
+		for i := range round.msgs {
+			encoded, err := abihelpers.EncodeExecutionReport(BuildExecutionReport(t, tree, round.msgs, []int{i}))
+			require.NoError(t, err)
+			round.execReports = append(round.execReports, encoded)
+		}
+		rounds = append(rounds, round)
+	}
+
+	configDetails, err := c.Dest.OffRamp.LatestConfigDetails(nil)
+	require.NoError(t, err)
+	// Epoch 1, round 0. Only the config digest is checked by the offRamp.
+	reportContext := [3][32]byte{configDetails.ConfigDigest, abihelpers.EvmWord(1 << 8), {}}
+	offRampABI, err := abi.JSON(strings.NewReader(evm_2_evm_offramp.EVM2EVMOffRampABI))
+	require.NoError(t, err)
+	rootNotCommitted := offRampABI.Errors["RootNotCommitted"].ID.Bytes()[:4]
+
+	commitReceipts := make([]*types.Receipt, len(rounds))
+	execReceipts := make([][]*types.Receipt, len(rounds))
+	var rejections int
+	// rejected carries the sequence numbers of rejected executions from the executor to the committer.
+	rejected := make(chan uint64, 1)
+
+	g, ctx := errgroup.WithContext(context.Background())
+	g.Go(func() error {
+		for r, round := range rounds {
+			for seqNum := uint64(0); seqNum != round.msgs[0].SequenceNumber; {
+				select {
+				case seqNum = <-rejected:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			tx, err := c.Dest.CommitStoreHelper.Report(c.Dest.User, round.report, big.NewInt(1))
+			if err != nil {
+				return errors.Wrapf(err, "failed to commit round %d", r)
+			}
+			if commitReceipts[r], err = c.mineDest(ctx, tx); err != nil {
+				return errors.Wrapf(err, "commit of round %d", r)
+			}
+		}
+		return nil
+	})
+	g.Go(func() error {
+		for r, round := range rounds {
+			for i, msg := range round.msgs {
+				for {
+					tx, err := c.Dest.OffRamp.Transmit(oracle.Transmitter, reportContext, round.execReports[i], nil, nil, [32]byte{})
+					if err == nil {
+						receipt, err := c.mineDest(ctx, tx)
+						if err != nil {
+							return errors.Wrapf(err, "execution of seqNum %d", msg.SequenceNumber)
+						}
+						execReceipts[r] = append(execReceipts[r], receipt)
+						break
+					}
+					var dataErr rpc.DataError
+					if !errors.As(err, &dataErr) {
+						return errors.Wrapf(err, "failed to execute seqNum %d", msg.SequenceNumber)
+					}
+					data, _ := dataErr.ErrorData().(string)
+					if decoded, _ := hexutil.Decode(data); !bytes.HasPrefix(decoded, rootNotCommitted) {
+						return errors.Errorf("execution of seqNum %d reverted with %s before its root was committed", msg.SequenceNumber, data)
+					}
+					rejections++
+					select {
+					case rejected <- msg.SequenceNumber:
+					default:
+					}
+					select {
+					case <-time.After(concurrentRoundRetryInterval):
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+			}
+		}
+		return nil
+	})
+	require.NoError(t, g.Wait())
+
+	require.GreaterOrEqual(t, rejections, len(rounds), "every round must reject an execution ahead of its commit")
+	for r, round := range rounds {
+		commit := commitReceipts[r]
+		require.Len(t, execReceipts[r], len(round.msgs))
+		for i, exec := range execReceipts[r] {
+			seqNum := round.msgs[i].SequenceNumber
+			executedAfterCommit := exec.BlockNumber.Cmp(commit.BlockNumber) > 0 ||
+				(exec.BlockNumber.Cmp(commit.BlockNumber) == 0 && exec.TransactionIndex > commit.TransactionIndex)
+			require.True(t, executedAfterCommit, "seqNum %d was executed before its root was committed", seqNum)
+			c.AssertExecStateForSeqNum(t, seqNum, abihelpers.ExecutionStateSuccess)
+		}
+	}
+}
+
+// mineDest mines tx on the dest chain and returns its receipt, failing if the transaction reverted. It does
+// not use the test, so that it can be called from other goroutines than the one running the test.
+func (c *CCIPContracts) mineDest(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	c.Dest.Chain.Commit()
+	receipt, err := bind.WaitMined(ctx, c.Dest.Chain, tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, errors.Errorf("transaction %s reverted", tx.Hash())
+	}
+	return receipt, nil
+}
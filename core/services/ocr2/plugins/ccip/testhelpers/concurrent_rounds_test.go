@@ -0,0 +1,10 @@
+package testhelpers
+
+import (
+	"testing"
+)
+
+func TestDriveConcurrentRounds(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	c.DriveConcurrentRounds(t, 8)
+}
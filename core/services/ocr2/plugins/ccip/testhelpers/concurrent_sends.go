@@ -0,0 +1,81 @@
+package testhelpers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+// SendConcurrentlyFromSender sends count data-only messages from sender through the source router from as many
+// goroutines, paying fees in LINK, and returns the sequence numbers the onRamp assigned them, in the order the
+// goroutines were started. Each goroutine builds its message and quotes its fee on its own, and only the
+// submission, which the nonce of sender must be assigned in, is serialized, as a transaction manager would.
+// All the sends are mined in a single block.
+func (c *CCIPContracts) SendConcurrentlyFromSender(t *testing.T, sender *bind.TransactOpts, count int) []uint64 {
+	require.Positive(t, count)
+	tx, err := c.Source.LinkToken.Approve(sender, c.Source.Router.Address(), HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(100_000), false)
+	require.NoError(t, err)
+	receiver := MustEncodeAddress(t, c.Dest.Receivers[0].Receiver.Address())
+
+	txs := make([]*types.Transaction, count)
+	var submit sync.Mutex
+	var g errgroup.Group
+	for i := 0; i < count; i++ {
+		i := i
+		g.Go(func() error {
+			msg := router.ClientEVM2AnyMessage{
+				Receiver:     receiver,
+				Data:         []byte(fmt.Sprintf("message %d", i)),
+				TokenAmounts: []router.ClientEVMTokenAmount{},
+				FeeToken:     c.Source.LinkToken.Address(),
+				ExtraArgs:    extraArgs,
+			}
+			if _, err := c.Source.Router.GetFee(&bind.CallOpts{From: sender.From}, c.Dest.ChainSelector, msg); err != nil {
+				return errors.Wrapf(err, "failed to quote message %d", i)
+			}
+			submit.Lock()
+			defer submit.Unlock()
+			opts := *sender
+			tx, err := c.Source.Router.CcipSend(&opts, c.Dest.ChainSelector, msg)
+			if err != nil {
+				return errors.Wrapf(err, "failed to send message %d", i)
+			}
+			txs[i] = tx
+			return nil
+		})
+	}
+	require.NoError(t, g.Wait())
+	c.Source.Chain.Commit()
+
+	seqNums := make([]uint64, count)
+	for i, tx := range txs {
+		receipt, err := bind.WaitMined(context.Background(), c.Source.Chain, tx)
+		require.NoError(t, err)
+		require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, "send of message %d reverted", i)
+		var sent bool
+		for _, log := range receipt.Logs {
+			if log.Address != c.Source.OnRamp.Address() {
+				continue
+			}
+			if requested, err := c.Source.OnRamp.ParseCCIPSendRequested(*log); err == nil {
+				seqNums[i] = requested.Message.SequenceNumber
+				sent = true
+			}
+		}
+		require.True(t, sent, "send of message %d did not emit CCIPSendRequested", i)
+	}
+	return seqNums
+}
@@ -0,0 +1,31 @@
+package testhelpers
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendConcurrentlyFromSender(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	sender := NewFundedUser(t, c.Source.Chain, c.Source.User)
+	tx, err := c.Source.LinkToken.Transfer(c.Source.User, sender.From, HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	next, err := c.Source.OnRamp.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+
+	const count = 20
+	seqNums := c.SendConcurrentlyFromSender(t, sender, count)
+	require.Len(t, seqNums, count)
+	sorted := append([]uint64{}, seqNums...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for i, seqNum := range sorted {
+		require.Equal(t, next+uint64(i), seqNum, "sequence numbers have gaps or duplicates: %v", seqNums)
+	}
+	nonce, err := c.Source.OnRamp.GetSenderNonce(nil, sender.From)
+	require.NoError(t, err)
+	require.Equal(t, uint64(count), nonce)
+}
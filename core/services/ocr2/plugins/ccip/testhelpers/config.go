@@ -24,15 +24,15 @@ func (c *CCIPContracts) CreateDefaultCommitOnchainConfig(t *testing.T) []byte {
 }
 
 func (c *CCIPContracts) CreateDefaultCommitOffchainConfig(t *testing.T) []byte {
-	return c.createCommitOffchainConfig(t, 10*time.Second, 5*time.Second)
+	return c.createCommitOffchainConfig(t, 10*time.Second, 5*time.Second, 1)
 }
 
-func (c *CCIPContracts) createCommitOffchainConfig(t *testing.T, feeUpdateHearBeat time.Duration, inflightCacheExpiry time.Duration) []byte {
+func (c *CCIPContracts) createCommitOffchainConfig(t *testing.T, feeUpdateHearBeat time.Duration, inflightCacheExpiry time.Duration, feeUpdateDeviationPPB uint32) []byte {
 	config, err := ccipconfig.EncodeOffchainConfig(ccipconfig.CommitOffchainConfig{
 		SourceFinalityDepth:   1,
 		DestFinalityDepth:     1,
 		FeeUpdateHeartBeat:    models.MustMakeDuration(feeUpdateHearBeat),
-		FeeUpdateDeviationPPB: 1,
+		FeeUpdateDeviationPPB: feeUpdateDeviationPPB,
 		MaxGasPrice:           200e9,
 		InflightCacheExpiry:   models.MustMakeDuration(inflightCacheExpiry),
 	})
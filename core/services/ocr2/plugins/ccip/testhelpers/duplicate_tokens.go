@@ -0,0 +1,92 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/generated/link_token_interface"
+)
+
+// BuildDuplicateTokenMessage builds a message to the first dest receiver that lists token once for every amount,
+// paying the fee in LINK.
+func (c *CCIPContracts) BuildDuplicateTokenMessage(t *testing.T, token common.Address, amounts []*big.Int) router.ClientEVM2AnyMessage {
+	require.Greater(t, len(amounts), 1, "a duplicate token message needs at least two amounts")
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	tokenAmounts := make([]router.ClientEVMTokenAmount, len(amounts))
+	for i, amount := range amounts {
+		tokenAmounts[i] = router.ClientEVMTokenAmount{Token: token, Amount: amount}
+	}
+	return router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, c.Dest.Receivers[0].Receiver.Address()),
+		Data:         []byte{},
+		TokenAmounts: tokenAmounts,
+		FeeToken:     c.Source.LinkToken.Address(),
+		ExtraArgs:    extraArgs,
+	}
+}
+
+// SendExpectingDuplicateTokenHandling sends msg, which may list the same token more than once, and asserts the
+// behavior of the onRamp for such messages: duplicates are neither rejected nor merged. Every entry is kept in
+// the sent message in its original position, and the pool of each token locks the sum of its amounts.
+func (c *CCIPContracts) SendExpectingDuplicateTokenHandling(t *testing.T, msg router.ClientEVM2AnyMessage) evm_2_evm_offramp.InternalEVM2EVMMessage {
+	fee, err := c.Source.Router.GetFee(nil, c.Dest.ChainSelector, msg)
+	require.NoError(t, err)
+
+	totals := make(map[common.Address]*big.Int)
+	for _, tokenAmount := range msg.TokenAmounts {
+		if totals[tokenAmount.Token] == nil {
+			totals[tokenAmount.Token] = big.NewInt(0)
+		}
+		totals[tokenAmount.Token].Add(totals[tokenAmount.Token], tokenAmount.Amount)
+	}
+	allowances := make(map[common.Address]*big.Int)
+	for token, total := range totals {
+		allowances[token] = new(big.Int).Set(total)
+	}
+	if allowances[msg.FeeToken] == nil {
+		allowances[msg.FeeToken] = big.NewInt(0)
+	}
+	allowances[msg.FeeToken].Add(allowances[msg.FeeToken], fee)
+
+	var txs []*types.Transaction
+	for token, allowance := range allowances {
+		erc20, err := link_token_interface.NewLinkToken(token, c.Source.Chain)
+		require.NoError(t, err)
+		tx, err := erc20.Approve(c.Source.User, c.Source.Router.Address(), allowance)
+		require.NoError(t, err)
+		txs = append(txs, tx)
+	}
+	ConfirmTxs(t, txs, c.Source.Chain)
+
+	pools := make(map[common.Address]common.Address)
+	poolBalancesBefore := make(map[common.Address]*big.Int)
+	for token := range totals {
+		pool, err := c.Source.OnRamp.GetPoolBySourceToken(nil, token)
+		require.NoError(t, err)
+		pools[token] = pool
+		poolBalancesBefore[token] = GetBalance(t, c.Source.Chain, token, pool)
+	}
+
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+	c.SendRequest(t, msg)
+	sent := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, sent, 1)
+
+	require.Len(t, sent[0].TokenAmounts, len(msg.TokenAmounts), "duplicate token entries were merged")
+	for i, tokenAmount := range msg.TokenAmounts {
+		require.Equal(t, tokenAmount.Token, sent[0].TokenAmounts[i].Token)
+		require.Equal(t, tokenAmount.Amount.String(), sent[0].TokenAmounts[i].Amount.String())
+	}
+	for token, total := range totals {
+		locked := new(big.Int).Sub(GetBalance(t, c.Source.Chain, token, pools[token]), poolBalancesBefore[token])
+		require.Equal(t, total.String(), locked.String(), "pool of %s did not lock the sum of its amounts", token)
+	}
+	return sent[0]
+}
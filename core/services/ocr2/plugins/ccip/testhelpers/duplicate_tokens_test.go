@@ -0,0 +1,20 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateTokenMessage(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	token := c.Source.LinkToken.Address()
+	amounts := []*big.Int{big.NewInt(1e18), big.NewInt(2e18)}
+	msg := c.BuildDuplicateTokenMessage(t, token, amounts)
+	require.Len(t, msg.TokenAmounts, 2)
+
+	sent := c.SendExpectingDuplicateTokenHandling(t, msg)
+	require.Equal(t, token, sent.TokenAmounts[0].Token)
+	require.Equal(t, token, sent.TokenAmounts[1].Token)
+}
@@ -0,0 +1,59 @@
+package testhelpers
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_onramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+// SendWithEmptyTokenArray sends data to receiver through the source router in a message with an explicitly empty,
+// rather than nil, token amounts array, paying the fee in native tokens. It returns the message as sent by the
+// onRamp, which must carry no token amounts.
+func (c *CCIPContracts) SendWithEmptyTokenArray(t *testing.T, receiver common.Address, data []byte) evm_2_evm_onramp.InternalEVM2EVMMessage {
+	msg := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, receiver),
+		Data:         data,
+		TokenAmounts: make([]router.ClientEVMTokenAmount, 0),
+		FeeToken:     common.Address{},
+		ExtraArgs:    EncodeExtraArgsVersion(t, 1, big.NewInt(200_000)),
+	}
+	fee, err := c.Source.Router.GetFee(&bind.CallOpts{From: c.Source.User.From}, c.Dest.ChainSelector, msg)
+	require.NoError(t, err)
+	opts := *c.Source.User
+	opts.Value = fee
+	tx, err := c.Source.Router.CcipSend(&opts, c.Dest.ChainSelector, msg)
+	require.NoError(t, err)
+	c.Source.Chain.Commit()
+	receipt, err := bind.WaitMined(context.Background(), c.Source.Chain, tx)
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+	for _, log := range receipt.Logs {
+		if log.Address != c.Source.OnRamp.Address() {
+			continue
+		}
+		if sent, err := c.Source.OnRamp.ParseCCIPSendRequested(*log); err == nil {
+			require.Empty(t, sent.Message.TokenAmounts)
+			return sent.Message
+		}
+	}
+	require.Fail(t, "send did not emit CCIPSendRequested")
+	return evm_2_evm_onramp.InternalEVM2EVMMessage{}
+}
+
+// AssertReceivedWithoutTokens asserts that receiver, as deployed by DeployRecordingReceiver, received the message
+// with seqNum exactly once, with data byte for byte want and no token amounts.
+func (c *CCIPContracts) AssertReceivedWithoutTokens(t *testing.T, receiver common.Address, seqNum uint64, want []byte) {
+	received := c.receivedMessage(t, receiver, seqNum)
+	require.Equal(t, hexutil.Encode(want), hexutil.Encode(received.Data), "data of seqNum %d was modified", seqNum)
+	require.Empty(t, received.DestTokenAmounts, "seqNum %d was received with tokens", seqNum)
+}
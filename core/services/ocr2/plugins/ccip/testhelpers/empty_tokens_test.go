@@ -0,0 +1,45 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestSendWithEmptyTokenArray(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	receiver := DeployRecordingReceiver(t, c.Dest.Chain, c.Dest.User)
+	data := []byte("data without tokens")
+
+	// An empty token amounts array encodes like a nil one, as the ABI only encodes its length.
+	routerABI, err := router.RouterMetaData.GetAbi()
+	require.NoError(t, err)
+	encode := func(tokenAmounts []router.ClientEVMTokenAmount) string {
+		encoded, err := routerABI.Pack("ccipSend", c.Dest.ChainSelector, router.ClientEVM2AnyMessage{
+			Receiver:     MustEncodeAddress(t, receiver),
+			Data:         data,
+			TokenAmounts: tokenAmounts,
+			ExtraArgs:    EncodeExtraArgsVersion(t, 1, big.NewInt(200_000)),
+		})
+		require.NoError(t, err)
+		return hexutil.Encode(encoded)
+	}
+	require.Equal(t, encode(nil), encode([]router.ClientEVMTokenAmount{}))
+
+	sent := c.SendWithEmptyTokenArray(t, receiver, data)
+	msgs := c.SendRequestedMessages(t, c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64())
+	require.Len(t, msgs, 1)
+	require.Equal(t, sent.SequenceNumber, msgs[0].SequenceNumber)
+	tree := c.CommitMessages(t, msgs)
+	_, err = c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0}))
+	require.NoError(t, err)
+
+	c.AssertExecStateForSeqNum(t, sent.SequenceNumber, abihelpers.ExecutionStateSuccess)
+	c.AssertReceivedWithoutTokens(t, receiver, sent.SequenceNumber, data)
+}
@@ -0,0 +1,57 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// SendToEOAReceiver sends a data-carrying message to the externally owned account eoa on the chain with
+// destSelector through r, paying the fee in native tokens. The returned transaction is not yet mined.
+func SendToEOAReceiver(t *testing.T, r *router.Router, sender *bind.TransactOpts, destSelector uint64, eoa common.Address) *types.Transaction {
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, eoa),
+		Data:         []byte("hello"),
+		TokenAmounts: []router.ClientEVMTokenAmount{},
+		FeeToken:     common.Address{},
+		ExtraArgs:    extraArgs,
+	}
+	fee, err := r.GetFee(&bind.CallOpts{From: sender.From}, destSelector, msg)
+	require.NoError(t, err)
+
+	opts := *sender
+	opts.Value = fee
+	tx, err := r.CcipSend(&opts, destSelector, msg)
+	require.NoError(t, err)
+	return tx
+}
+
+// AssertExecutedWithoutReceiverCall asserts that the execution in receipt marked the message with seqNum as
+// successful, and that the offRamp did not route the message to its receiver.
+func (c *CCIPContracts) AssertExecutedWithoutReceiverCall(t *testing.T, receipt *types.Receipt, seqNum uint64) {
+	var executed bool
+	for _, log := range receipt.Logs {
+		require.NotEqual(t, c.Dest.Router.Address(), log.Address, "message was routed to its receiver")
+		if log.Address != c.Dest.OffRamp.Address() {
+			continue
+		}
+		stateChanged, err := c.Dest.OffRamp.ParseExecutionStateChanged(*log)
+		if err != nil || stateChanged.SequenceNumber != seqNum {
+			continue
+		}
+		require.Equal(t, abihelpers.ExecutionStateSuccess, abihelpers.MessageExecutionState(stateChanged.State))
+		require.Empty(t, stateChanged.ReturnData)
+		executed = true
+	}
+	require.True(t, executed, "no execution of seqNum %d in receipt", seqNum)
+	c.AssertExecStateForSeqNum(t, seqNum, abihelpers.ExecutionStateSuccess)
+}
@@ -0,0 +1,32 @@
+package testhelpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryToEOAReceiver(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	eoa := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	code, err := c.Dest.Chain.CodeAt(context.Background(), eoa, nil)
+	require.NoError(t, err)
+	require.Empty(t, code)
+
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+	tx := SendToEOAReceiver(t, c.Source.Router, c.Source.User, c.Dest.ChainSelector, eoa)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 1)
+	require.Equal(t, eoa, msgs[0].Receiver)
+	require.NotEmpty(t, msgs[0].Data)
+
+	tree := c.CommitMessages(t, msgs)
+	receipt, err := c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0}))
+	require.NoError(t, err)
+	c.AssertExecutedWithoutReceiverCall(t, receipt, msgs[0].SequenceNumber)
+}
@@ -0,0 +1,30 @@
+package testhelpers
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/asm"
+	"github.com/stretchr/testify/require"
+)
+
+// assemble compiles EVM assembly in the syntax of go-ethereum's asm package into bytecode.
+func assemble(t *testing.T, source string) []byte {
+	compiler := asm.NewCompiler(false)
+	compiler.Feed(asm.Lex([]byte(source), false))
+	compiled, errs := compiler.Compile()
+	require.Empty(t, errs)
+	code, err := hex.DecodeString(compiled)
+	require.NoError(t, err)
+	return code
+}
+
+// creationCode returns the creation code of a contract with the given runtime code and no constructor logic.
+func creationCode(runtime []byte) []byte {
+	initCode := []byte{
+		0x61, byte(len(runtime) >> 8), byte(len(runtime)), 0x80, // PUSH2 len, DUP1
+		0x60, 0x0c, 0x60, 0x00, 0x39, // CODECOPY(0, 12, len)
+		0x60, 0x00, 0xf3, // RETURN(0, len)
+	}
+	return append(initCode, runtime...)
+}
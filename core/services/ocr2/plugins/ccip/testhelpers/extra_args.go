@@ -0,0 +1,107 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_onramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+// evmExtraArgsV2Tag is bytes4(keccak256("CCIP EVMExtraArgsV2")), the tag of the extraArgs version that adds
+// allowOutOfOrderExecution to the gas limit. The onRamps of this version do not support it.
+var evmExtraArgsV2Tag = []byte{0x18, 0x1d, 0xcf, 0x10}
+
+// EncodeExtraArgsVersion encodes extraArgs with gasLimit in the given version. Version 0 encodes no extraArgs,
+// for which onRamps fall back to their default gas limit.
+func EncodeExtraArgsVersion(t *testing.T, version int, gasLimit *big.Int) []byte {
+	switch version {
+	case 0:
+		return []byte{}
+	case 1:
+		extraArgs, err := GetEVMExtraArgsV1(gasLimit, false)
+		require.NoError(t, err)
+		return extraArgs
+	case 2:
+		return EncodeExtraArgsV2(t, gasLimit, false)
+	default:
+		require.FailNow(t, "unknown extraArgs version", "version %d", version)
+		return nil
+	}
+}
+
+// EncodeExtraArgsV2 encodes V2 extraArgs with gasLimit and the allowOutOfOrderExecution flag, which exempts a
+// message from the sender nonce ordering on the offRamps that support it.
+func EncodeExtraArgsV2(t *testing.T, gasLimit *big.Int, allowOutOfOrderExecution bool) []byte {
+	encoded, err := utils.ABIEncode(`[{"type":"uint256"},{"type":"bool"}]`, gasLimit, allowOutOfOrderExecution)
+	require.NoError(t, err)
+	return append(append([]byte{}, evmExtraArgsV2Tag...), encoded...)
+}
+
+// SendWithExtraArgsVersion sends a data-only message to sender on the chain with destSelector through r, with
+// its extraArgs encoded in the given version, paying the fee in native tokens. The fee is quoted with V1
+// extraArgs, so that the send itself is what handles the version. Errors returned by the router are passed to
+// the caller, and a returned transaction is not yet mined.
+func SendWithExtraArgsVersion(t *testing.T, r *router.Router, sender *bind.TransactOpts, destSelector uint64, version int) (*types.Transaction, error) {
+	gasLimit := big.NewInt(200_000)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, sender.From),
+		Data:         []byte("hello"),
+		TokenAmounts: []router.ClientEVMTokenAmount{},
+		FeeToken:     common.Address{},
+		ExtraArgs:    EncodeExtraArgsVersion(t, 1, gasLimit),
+	}
+	return sendWithQuotedExtraArgs(t, r, sender, destSelector, msg, EncodeExtraArgsVersion(t, version, gasLimit))
+}
+
+// SendAllowingOutOfOrder sends a data-only message to sender on the chain with destSelector through r, with V2
+// extraArgs allowing it to be executed out of order, paying the fee quoted for V1 extraArgs in native tokens.
+// Errors returned by the router are passed to the caller, and a returned transaction is not yet mined.
+func SendAllowingOutOfOrder(t *testing.T, r *router.Router, sender *bind.TransactOpts, destSelector uint64) (*types.Transaction, error) {
+	gasLimit := big.NewInt(200_000)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, sender.From),
+		Data:         []byte("hello"),
+		TokenAmounts: []router.ClientEVMTokenAmount{},
+		FeeToken:     common.Address{},
+		ExtraArgs:    EncodeExtraArgsVersion(t, 1, gasLimit),
+	}
+	return sendWithQuotedExtraArgs(t, r, sender, destSelector, msg, EncodeExtraArgsV2(t, gasLimit, true))
+}
+
+// AssertOutOfOrderExecutionUnsupported asserts that a send allowing out of order execution was rejected with
+// InvalidExtraArgsTag. The onRamps of this version do not decode V2 extraArgs, and the offRamps execute the
+// messages of every sender in nonce order, skipping the messages whose nonce is not the next one expected.
+func AssertOutOfOrderExecutionUnsupported(t *testing.T, err error) {
+	AssertRevertedWith(t, err, evm_2_evm_onramp.EVM2EVMOnRampABI, "InvalidExtraArgsTag")
+}
+
+// sendWithQuotedExtraArgs quotes the fee of msg, and sends it through r with extraArgs swapped in, attaching the
+// quoted fee in native tokens.
+func sendWithQuotedExtraArgs(t *testing.T, r *router.Router, sender *bind.TransactOpts, destSelector uint64, msg router.ClientEVM2AnyMessage, extraArgs []byte) (*types.Transaction, error) {
+	fee, err := r.GetFee(&bind.CallOpts{From: sender.From}, destSelector, msg)
+	require.NoError(t, err)
+
+	msg.ExtraArgs = extraArgs
+	opts := *sender
+	opts.Value = fee
+	return r.CcipSend(&opts, destSelector, msg)
+}
+
+// AssertExtraArgsVersionHandled asserts the outcome err of a send with extraArgs of the given version. The onRamp
+// only decodes V1 extraArgs, and empty extraArgs for the default gas limit, and rejects the tag of every other
+// version, including the newer V2, with InvalidExtraArgsTag rather than downgrading it.
+func AssertExtraArgsVersionHandled(t *testing.T, version int, err error) {
+	switch version {
+	case 0, 1:
+		require.NoError(t, err, "extraArgs version %d should be accepted", version)
+	default:
+		AssertRevertedWith(t, err, evm_2_evm_onramp.EVM2EVMOnRampABI, "InvalidExtraArgsTag")
+	}
+}
@@ -0,0 +1,37 @@
+package testhelpers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendWithExtraArgsVersion(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	for _, version := range []int{0, 1, 2} {
+		t.Run(fmt.Sprintf("v%d", version), func(t *testing.T) {
+			tx, err := SendWithExtraArgsVersion(t, c.Source.Router, c.Source.User, c.Dest.ChainSelector, version)
+			AssertExtraArgsVersionHandled(t, version, err)
+			if err == nil {
+				ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+			}
+		})
+	}
+}
+
+func TestSendAllowingOutOfOrder(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	seqNum, err := c.Source.OnRamp.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = SendAllowingOutOfOrder(t, c.Source.Router, c.Source.User, c.Dest.ChainSelector)
+		AssertOutOfOrderExecutionUnsupported(t, err)
+	}
+	c.Source.Chain.Commit()
+	after, err := c.Source.OnRamp.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, seqNum, after)
+}
@@ -0,0 +1,86 @@
+package testhelpers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// gasGuzzlerReceiverRuntime is the assembly of a receiver whose ccipReceive loops until it runs out of gas.
+const gasGuzzlerReceiverRuntime = `
+PUSH 0
+CALLDATALOAD
+PUSH 224
+SHR
+DUP1
+PUSH 0x01ffc9a7
+EQ
+JUMPI @supportsInterface
+PUSH %[1]s
+EQ
+JUMPI @ccipReceive
+PUSH 0
+DUP1
+REVERT
+
+ccipReceive:
+JUMP @ccipReceive
+` + receiverSupportsInterface
+
+// DeployGasGuzzlerReceiver deploys a message receiver that consumes all the gas it is given for every message,
+// whatever the gas limit of the message.
+func DeployGasGuzzlerReceiver(t *testing.T, chain *backends.SimulatedBackend, owner *bind.TransactOpts) common.Address {
+	return deployReceiver(t, chain, owner, assemble(t, fmt.Sprintf(gasGuzzlerReceiverRuntime, ccipReceiveSelector(t))))
+}
+
+// receiverFailureData returns the data the receiver of the message with seqNum failed with in the execution in
+// receipt, which must have marked the message as failed with ReceiverError.
+func (c *CCIPContracts) receiverFailureData(t *testing.T, receipt *types.Receipt, seqNum uint64) []byte {
+	offRampABI, err := evm_2_evm_offramp.EVM2EVMOffRampMetaData.GetAbi()
+	require.NoError(t, err)
+	receiverError := offRampABI.Errors["ReceiverError"]
+	for _, log := range receipt.Logs {
+		if log.Address != c.Dest.OffRamp.Address() {
+			continue
+		}
+		stateChanged, err := c.Dest.OffRamp.ParseExecutionStateChanged(*log)
+		if err != nil || stateChanged.SequenceNumber != seqNum {
+			continue
+		}
+		require.Equal(t, abihelpers.ExecutionStateFailure, abihelpers.MessageExecutionState(stateChanged.State))
+		AssertErrorSelector(t, stateChanged.ReturnData, evm_2_evm_offramp.EVM2EVMOffRampABI, "ReceiverError")
+		args, err := receiverError.Inputs.Unpack(stateChanged.ReturnData[4:])
+		require.NoError(t, err)
+		data, ok := args[0].([]byte)
+		require.True(t, ok, "unexpected ReceiverError argument %T", args[0])
+		return data
+	}
+	require.Fail(t, "no execution in receipt", "seqNum %d", seqNum)
+	return nil
+}
+
+// AssertFailureReasonOutOfGas asserts that the execution in receipt failed the message with seqNum because its
+// receiver ran out of gas. A receiver out of gas is aborted without return data, so the offRamp captures a
+// ReceiverError with empty data.
+func (c *CCIPContracts) AssertFailureReasonOutOfGas(t *testing.T, receipt *types.Receipt, seqNum uint64) {
+	data := c.receiverFailureData(t, receipt, seqNum)
+	require.Empty(t, data, "seqNum %d failed with receiver revert data %s", seqNum, hexutil.Encode(data))
+}
+
+// AssertFailureReasonRevert asserts that the execution in receipt failed the message with seqNum because its
+// receiver reverted with a reason, and returns the revert data the offRamp captured in the ReceiverError.
+// Reverts without data cannot be told apart from running out of gas.
+func (c *CCIPContracts) AssertFailureReasonRevert(t *testing.T, receipt *types.Receipt, seqNum uint64) []byte {
+	data := c.receiverFailureData(t, receipt, seqNum)
+	require.NotEmpty(t, data, "seqNum %d failed without receiver revert data", seqNum)
+	return data
+}
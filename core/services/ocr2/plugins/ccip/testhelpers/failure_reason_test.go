@@ -0,0 +1,49 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/maybe_revert_message_receiver"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+func TestAssertFailureReason(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	tx, err := c.Source.LinkToken.Approve(c.Source.User, c.Source.Router.Address(), HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+
+	guzzler := DeployGasGuzzlerReceiver(t, c.Dest.Chain, c.Dest.User)
+	reverter := c.Dest.Receivers[0].Receiver
+	tx, err = reverter.SetRevert(c.Dest.User, true)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	for _, receiver := range []common.Address{guzzler, reverter.Address()} {
+		c.SendRequest(t, router.ClientEVM2AnyMessage{
+			Receiver:     MustEncodeAddress(t, receiver),
+			Data:         []byte("hello"),
+			TokenAmounts: []router.ClientEVMTokenAmount{},
+			FeeToken:     c.Source.LinkToken.Address(),
+			ExtraArgs:    extraArgs,
+		})
+	}
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 2)
+
+	tree := c.CommitMessages(t, msgs)
+	receipt, err := c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0, 1}))
+	require.NoError(t, err)
+	c.AssertFailureReasonOutOfGas(t, receipt, msgs[0].SequenceNumber)
+	data := c.AssertFailureReasonRevert(t, receipt, msgs[1].SequenceNumber)
+	AssertErrorSelector(t, data, maybe_revert_message_receiver.MaybeRevertMessageReceiverABI, "CustomError")
+}
@@ -0,0 +1,177 @@
+package testhelpers
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// ErrInjectedFault is returned by a FaultInjectingBackend for the calls it fails.
+var ErrInjectedFault = errors.New("injected rpc fault")
+
+var _ bind.ContractBackend = (*FaultInjectingBackend)(nil)
+
+// FaultInjectingBackend wraps a contract backend, such as a simulated backend, to behave like a flaky RPC.
+// Every call is delayed by the configured latency, and then fails with ErrInjectedFault with the configured
+// failure rate, without reaching the wrapped backend. Faults are drawn from a seeded source, so that a test
+// sees the same faults on every run as long as it makes the same calls.
+type FaultInjectingBackend struct {
+	backend bind.ContractBackend
+
+	failureRate float64
+	latency     time.Duration
+	rand        *rand.Rand
+	calls       int
+	faults      int
+
+	mu sync.Mutex
+}
+
+// NewFaultInjectingBackend wraps backend, which it does not fail or delay any calls to until configured to.
+func NewFaultInjectingBackend(backend bind.ContractBackend, seed int64) *FaultInjectingBackend {
+	return &FaultInjectingBackend{
+		backend: backend,
+		rand:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// SetFailureRate sets the fraction of calls, between 0 and 1, that fail.
+func (b *FaultInjectingBackend) SetFailureRate(rate float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failureRate = rate
+}
+
+// SetLatency sets the delay added to every call.
+func (b *FaultInjectingBackend) SetLatency(latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.latency = latency
+}
+
+// Calls returns the number of calls made to the backend, including the failed ones.
+func (b *FaultInjectingBackend) Calls() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.calls
+}
+
+// Faults returns the number of calls failed with ErrInjectedFault.
+func (b *FaultInjectingBackend) Faults() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.faults
+}
+
+// inject delays the call of the given method and decides whether it fails. Calls whose context is done while
+// delayed fail with the error of the context.
+func (b *FaultInjectingBackend) inject(ctx context.Context, method string) error {
+	b.mu.Lock()
+	b.calls++
+	latency := b.latency
+	fail := b.rand.Float64() < b.failureRate
+	if fail {
+		b.faults++
+	}
+	b.mu.Unlock()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if fail {
+		return errors.Wrap(ErrInjectedFault, method)
+	}
+	return nil
+}
+
+func (b *FaultInjectingBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	if err := b.inject(ctx, "CodeAt"); err != nil {
+		return nil, err
+	}
+	return b.backend.CodeAt(ctx, contract, blockNumber)
+}
+
+func (b *FaultInjectingBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if err := b.inject(ctx, "CallContract"); err != nil {
+		return nil, err
+	}
+	return b.backend.CallContract(ctx, call, blockNumber)
+}
+
+func (b *FaultInjectingBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if err := b.inject(ctx, "HeaderByNumber"); err != nil {
+		return nil, err
+	}
+	return b.backend.HeaderByNumber(ctx, number)
+}
+
+func (b *FaultInjectingBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	if err := b.inject(ctx, "PendingCodeAt"); err != nil {
+		return nil, err
+	}
+	return b.backend.PendingCodeAt(ctx, account)
+}
+
+func (b *FaultInjectingBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	if err := b.inject(ctx, "PendingNonceAt"); err != nil {
+		return 0, err
+	}
+	return b.backend.PendingNonceAt(ctx, account)
+}
+
+func (b *FaultInjectingBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	if err := b.inject(ctx, "SuggestGasPrice"); err != nil {
+		return nil, err
+	}
+	return b.backend.SuggestGasPrice(ctx)
+}
+
+func (b *FaultInjectingBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	if err := b.inject(ctx, "SuggestGasTipCap"); err != nil {
+		return nil, err
+	}
+	return b.backend.SuggestGasTipCap(ctx)
+}
+
+func (b *FaultInjectingBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	if err := b.inject(ctx, "EstimateGas"); err != nil {
+		return 0, err
+	}
+	return b.backend.EstimateGas(ctx, call)
+}
+
+func (b *FaultInjectingBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.inject(ctx, "SendTransaction"); err != nil {
+		return err
+	}
+	return b.backend.SendTransaction(ctx, tx)
+}
+
+func (b *FaultInjectingBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if err := b.inject(ctx, "FilterLogs"); err != nil {
+		return nil, err
+	}
+	return b.backend.FilterLogs(ctx, query)
+}
+
+func (b *FaultInjectingBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	if err := b.inject(ctx, "SubscribeFilterLogs"); err != nil {
+		return nil, err
+	}
+	return b.backend.SubscribeFilterLogs(ctx, query, ch)
+}
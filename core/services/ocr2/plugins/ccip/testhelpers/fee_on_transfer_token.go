@@ -0,0 +1,233 @@
+package testhelpers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/generated/link_token_interface"
+)
+
+// feeOnTransferTokenSupply is minted to the deployer of a fee-on-transfer token, the same supply as a LinkToken.
+var feeOnTransferTokenSupply = new(big.Int).Mul(big.NewInt(1e9), big.NewInt(1e18))
+
+// feeOnTransferTokenRuntime is the assembly of a minimal ERC20 without events that burns a fee of every transfer,
+// with balances at the slots of their accounts and allowances at keccak256(owner, spender).
+// %[1]d is the fee in basis points and %[2]s the approve guard, which must leave the allowance slot on the stack.
+const feeOnTransferTokenRuntime = `
+PUSH 0
+CALLDATALOAD
+PUSH 224
+SHR
+DUP1
+PUSH 0x70a08231
+EQ
+JUMPI @balanceOf
+DUP1
+PUSH 0xdd62ed3e
+EQ
+JUMPI @allowance
+DUP1
+PUSH 0x095ea7b3
+EQ
+JUMPI @approve
+DUP1
+PUSH 0xa9059cbb
+EQ
+JUMPI @transfer
+DUP1
+PUSH 0x23b872dd
+EQ
+JUMPI @transferFrom
+DUP1
+PUSH 0x313ce567
+EQ
+JUMPI @decimals
+JUMP @fail
+
+balanceOf:
+PUSH 4
+CALLDATALOAD
+SLOAD
+JUMP @returnWord
+
+allowance:
+PUSH 4
+CALLDATALOAD
+PUSH 0
+MSTORE
+PUSH 36
+CALLDATALOAD
+PUSH 32
+MSTORE
+PUSH 64
+PUSH 0
+KECCAK256
+SLOAD
+JUMP @returnWord
+
+approve:
+CALLER
+PUSH 0
+MSTORE
+PUSH 4
+CALLDATALOAD
+PUSH 32
+MSTORE
+PUSH 64
+PUSH 0
+KECCAK256
+%[2]s
+PUSH 36
+CALLDATALOAD
+SWAP1
+SSTORE
+JUMP @returnTrue
+
+transfer:
+CALLER
+PUSH 4
+CALLDATALOAD
+PUSH 36
+CALLDATALOAD
+JUMP @move
+
+transferFrom:
+PUSH 4
+CALLDATALOAD
+PUSH 0
+MSTORE
+CALLER
+PUSH 32
+MSTORE
+PUSH 64
+PUSH 0
+KECCAK256
+DUP1
+SLOAD
+PUSH 68
+CALLDATALOAD
+DUP1
+DUP3
+LT
+JUMPI @fail
+SWAP1
+SUB
+SWAP1
+SSTORE
+PUSH 4
+CALLDATALOAD
+PUSH 36
+CALLDATALOAD
+PUSH 68
+CALLDATALOAD
+JUMP @move
+
+decimals:
+PUSH 18
+JUMP @returnWord
+
+;; stack: from, to, amount
+move:
+DUP3
+SLOAD
+DUP1
+DUP3
+GT
+JUMPI @fail
+DUP2
+SWAP1
+SUB
+DUP4
+SSTORE
+DUP1
+PUSH %[1]d
+MUL
+PUSH 10000
+SWAP1
+DIV
+SWAP1
+SUB
+DUP2
+SLOAD
+ADD
+SWAP1
+SSTORE
+POP
+JUMP @returnTrue
+
+returnTrue:
+PUSH 1
+JUMP @returnWord
+
+returnWord:
+PUSH 0
+MSTORE
+PUSH 32
+PUSH 0
+RETURN
+
+fail:
+PUSH 0
+DUP1
+REVERT
+`
+
+// feeOnTransferTokenCode returns the creation code of a fee-on-transfer token charging feeBps and guarding
+// approvals with approveGuard, which mints feeOnTransferTokenSupply to the deployer.
+func feeOnTransferTokenCode(t *testing.T, feeBps uint16, approveGuard string) []byte {
+	runtime := assemble(t, fmt.Sprintf(feeOnTransferTokenRuntime, feeBps, approveGuard))
+
+	initCode := []byte{0x7f} // PUSH32 supply
+	initCode = append(initCode, common.LeftPadBytes(feeOnTransferTokenSupply.Bytes(), 32)...)
+	initCode = append(initCode,
+		0x33, 0x55, // SSTORE(CALLER, supply)
+		0x61, byte(len(runtime)>>8), byte(len(runtime)), 0x80, // PUSH2 len, DUP1
+		0x60, 0x2f, 0x60, 0x00, 0x39, // CODECOPY(0, 47, len)
+		0x60, 0x00, 0xf3, // RETURN(0, len)
+	)
+	return append(initCode, runtime...)
+}
+
+// DeployFeeOnTransferToken deploys an ERC20 that burns feeBps basis points of every transfer, so that the
+// recipient of a transfer receives less than the transferred amount. The whole supply is minted to owner.
+// The token is returned bound as a LinkToken, which covers the ERC20 functions it implements.
+func DeployFeeOnTransferToken(t *testing.T, chain *backends.SimulatedBackend, owner *bind.TransactOpts, feeBps uint16) (*link_token_interface.LinkToken, common.Address) {
+	require.LessOrEqual(t, feeBps, uint16(10000), "fee cannot exceed the transferred amount")
+	return deployToken(t, chain, owner, feeOnTransferTokenCode(t, feeBps, ""))
+}
+
+// deployToken deploys a token with the given creation code and binds it as a LinkToken.
+func deployToken(t *testing.T, chain *backends.SimulatedBackend, owner *bind.TransactOpts, initCode []byte) (*link_token_interface.LinkToken, common.Address) {
+	address, tx, _, err := bind.DeployContract(owner, abi.ABI{}, initCode, chain)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+	code, err := chain.CodeAt(context.Background(), address, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, code)
+	token, err := link_token_interface.NewLinkToken(address, chain)
+	require.NoError(t, err)
+	return token, address
+}
+
+// AssertTransferAccountsForFee asserts how a transfer of sent fee-on-transfer tokens charging feeBps is accounted
+// for along a lane, given msg as sent by the onRamp, the amount locked by the source pool and the amount released
+// to the receiver on the dest chain. Pools do not reject such tokens, and nothing on the lane accounts for the fee:
+// the source pool only receives the amount net of the fee, but the message carries the full sent amount and the
+// dest pool releases all of it. Every such transfer leaves the dest pool short by the fee.
+func AssertTransferAccountsForFee(t *testing.T, sent *big.Int, feeBps uint16, msg evm_2_evm_offramp.InternalEVM2EVMMessage, locked, released *big.Int) {
+	fee := new(big.Int).Div(new(big.Int).Mul(sent, big.NewInt(int64(feeBps))), big.NewInt(10000))
+	require.Equal(t, new(big.Int).Sub(sent, fee).String(), locked.String(), "source pool did not receive the amount net of the fee")
+	require.Len(t, msg.TokenAmounts, 1)
+	require.Equal(t, sent.String(), msg.TokenAmounts[0].Amount.String(), "message does not carry the sent amount")
+	require.Equal(t, sent.String(), released.String(), "dest pool did not release the amount in the message")
+}
@@ -0,0 +1,107 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_onramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/lock_release_token_pool"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/generated/link_token_interface"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestFeeOnTransferToken(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	const feeBps = 100
+	sourceToken, sourceTokenAddress := DeployFeeOnTransferToken(t, c.Source.Chain, c.Source.User, feeBps)
+
+	// The token itself burns the fee on every transfer.
+	other := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	tx, err := sourceToken.Transfer(c.Source.User, other, big.NewInt(10000))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	require.Equal(t, "9900", GetBalance(t, c.Source.Chain, sourceTokenAddress, other).String())
+
+	// Lock the token on the source chain, and release a regular token for it on the dest chain.
+	sourcePoolAddress, _, _, err := lock_release_token_pool.DeployLockReleaseTokenPool(c.Source.User, c.Source.Chain, sourceTokenAddress, []common.Address{}, c.Source.ARMProxy.Address(), true)
+	require.NoError(t, err)
+	c.Source.Chain.Commit()
+	sourcePool, err := lock_release_token_pool.NewLockReleaseTokenPool(sourcePoolAddress, c.Source.Chain)
+	require.NoError(t, err)
+	rateLimiterConfig := lock_release_token_pool.RateLimiterConfig{IsEnabled: true, Capacity: HundredLink, Rate: big.NewInt(1e18)}
+	tx, err = sourcePool.ApplyRampUpdates(c.Source.User, []lock_release_token_pool.TokenPoolRampUpdate{{Ramp: c.Source.OnRamp.Address(), Allowed: true, RateLimiterConfig: rateLimiterConfig}}, nil)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	tx, err = c.Source.OnRamp.ApplyPoolUpdates(c.Source.User, nil, []evm_2_evm_onramp.InternalPoolUpdate{{Token: sourceTokenAddress, Pool: sourcePoolAddress}})
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	c.SetSourceTokenPrice(t, sourceTokenAddress, big.NewInt(1e18))
+
+	destTokenAddress, _, _, err := link_token_interface.DeployLinkToken(c.Dest.User, c.Dest.Chain)
+	require.NoError(t, err)
+	c.Dest.Chain.Commit()
+	destPoolAddress, _, _, err := lock_release_token_pool.DeployLockReleaseTokenPool(c.Dest.User, c.Dest.Chain, destTokenAddress, []common.Address{}, c.Dest.ARMProxy.Address(), true)
+	require.NoError(t, err)
+	c.Dest.Chain.Commit()
+	destPool, err := lock_release_token_pool.NewLockReleaseTokenPool(destPoolAddress, c.Dest.Chain)
+	require.NoError(t, err)
+	tx, err = destPool.ApplyRampUpdates(c.Dest.User, nil, []lock_release_token_pool.TokenPoolRampUpdate{{Ramp: c.Dest.OffRamp.Address(), Allowed: true, RateLimiterConfig: rateLimiterConfig}})
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	destToken, err := link_token_interface.NewLinkToken(destTokenAddress, c.Dest.Chain)
+	require.NoError(t, err)
+	tx, err = destToken.Approve(c.Dest.User, destPoolAddress, HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	tx, err = destPool.AddLiquidity(c.Dest.User, HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	tx, err = c.Dest.OffRamp.ApplyPoolUpdates(c.Dest.User, nil, []evm_2_evm_offramp.InternalPoolUpdate{{Token: sourceTokenAddress, Pool: destPoolAddress}})
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	tx, err = c.Dest.PriceRegistry.UpdatePrices(c.Dest.User, tokenPriceUpdate(destTokenAddress, big.NewInt(1e18)))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+
+	// Send the token through the lane.
+	amount := new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18))
+	receiver := c.Dest.Receivers[0].Receiver.Address()
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, receiver),
+		Data:         []byte{},
+		TokenAmounts: []router.ClientEVMTokenAmount{{Token: sourceTokenAddress, Amount: amount}},
+		FeeToken:     c.Source.LinkToken.Address(),
+		ExtraArgs:    extraArgs,
+	}
+	fee, err := c.Source.Router.GetFee(nil, c.Dest.ChainSelector, msg)
+	require.NoError(t, err)
+	tx, err = c.Source.LinkToken.Approve(c.Source.User, c.Source.Router.Address(), fee)
+	require.NoError(t, err)
+	tx2, err := sourceToken.Approve(c.Source.User, c.Source.Router.Address(), amount)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx, tx2}, c.Source.Chain)
+
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+	c.SendRequest(t, msg)
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 1)
+	locked := GetBalance(t, c.Source.Chain, sourceTokenAddress, sourcePoolAddress)
+
+	tree := c.CommitMessages(t, msgs)
+	_, err = c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0}))
+	require.NoError(t, err)
+	c.AssertExecStateForSeqNum(t, msgs[0].SequenceNumber, abihelpers.ExecutionStateSuccess)
+	released := GetBalance(t, c.Dest.Chain, destTokenAddress, receiver)
+
+	AssertTransferAccountsForFee(t, amount, feeBps, msgs[0], locked, released)
+	require.Equal(t, new(big.Int).Sub(HundredLink, amount).String(), GetBalance(t, c.Dest.Chain, destTokenAddress, destPoolAddress).String())
+}
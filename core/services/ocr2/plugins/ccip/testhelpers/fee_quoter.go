@@ -0,0 +1,65 @@
+package testhelpers
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+// fixedFeeQuoterCode returns the creation code of a contract that answers every call with fee, and therefore
+// quotes fee from getFee for any message.
+func fixedFeeQuoterCode(fee *big.Int) []byte {
+	runtime := []byte{0x7f} // PUSH32 fee
+	runtime = append(runtime, common.LeftPadBytes(fee.Bytes(), 32)...)
+	runtime = append(runtime,
+		0x60, 0x00, 0x52, // MSTORE(0, fee)
+		0x60, 0x20, 0x60, 0x00, 0xf3, // RETURN(0, 32)
+	)
+	initCode := []byte{
+		0x60, byte(len(runtime)), 0x80, // PUSH1 len, DUP1
+		0x60, 0x0b, 0x60, 0x00, 0x39, // CODECOPY(0, 11, len)
+		0x60, 0x00, 0xf3, // RETURN(0, len)
+	}
+	return append(initCode, runtime...)
+}
+
+// DeployFixedFeeQuoter deploys a fee quoter that quotes fee for every message, regardless of its contents.
+func DeployFixedFeeQuoter(t *testing.T, chain *backends.SimulatedBackend, owner *bind.TransactOpts, fee *big.Int) common.Address {
+	address, tx, _, err := bind.DeployContract(owner, abi.ABI{}, fixedFeeQuoterCode(fee), chain)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+	code, err := chain.CodeAt(context.Background(), address, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, code)
+	return address
+}
+
+// SetFeeQuoter installs quoter as the contract r asks for fees of messages to destSelector. The router quotes
+// fees through the onRamp of the destination, so this replaces that onRamp.
+func SetFeeQuoter(t *testing.T, chain *backends.SimulatedBackend, r *router.Router, owner *bind.TransactOpts, destSelector uint64, quoter common.Address) {
+	tx, err := r.ApplyRampUpdates(owner, []router.RouterOnRamp{{DestChainSelector: destSelector, OnRamp: quoter}}, nil, nil)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+}
+
+// AssertFeeFromQuoter asserts that r quotes fees for messages to destSelector through quoter, and that each of
+// msgs is quoted exactly expectedFee.
+func AssertFeeFromQuoter(t *testing.T, r *router.Router, destSelector uint64, quoter common.Address, expectedFee *big.Int, msgs ...router.ClientEVM2AnyMessage) {
+	onRamp, err := r.GetOnRamp(nil, destSelector)
+	require.NoError(t, err)
+	require.Equal(t, quoter, onRamp)
+	for _, msg := range msgs {
+		fee, err := r.GetFee(nil, destSelector, msg)
+		require.NoError(t, err)
+		require.Equal(t, expectedFee.String(), fee.String())
+	}
+}
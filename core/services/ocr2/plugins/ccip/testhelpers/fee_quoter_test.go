@@ -0,0 +1,43 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+func TestFixedFeeQuoter(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	small := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, c.Dest.Receivers[0].Receiver.Address()),
+		Data:         []byte{},
+		TokenAmounts: []router.ClientEVMTokenAmount{},
+		FeeToken:     c.Source.LinkToken.Address(),
+		ExtraArgs:    extraArgs,
+	}
+	large := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, c.Dest.Receivers[0].Receiver.Address()),
+		Data:         make([]byte, 10_000),
+		TokenAmounts: []router.ClientEVMTokenAmount{{Token: c.Source.LinkToken.Address(), Amount: big.NewInt(1e18)}},
+		FeeToken:     common.Address{},
+		ExtraArgs:    extraArgs,
+	}
+
+	// The onRamp quotes fees depending on the message.
+	smallFee, err := c.Source.Router.GetFee(nil, c.Dest.ChainSelector, small)
+	require.NoError(t, err)
+	largeFee, err := c.Source.Router.GetFee(nil, c.Dest.ChainSelector, large)
+	require.NoError(t, err)
+	require.NotEqual(t, smallFee.String(), largeFee.String())
+
+	fixedFee := big.NewInt(123_456)
+	quoter := DeployFixedFeeQuoter(t, c.Source.Chain, c.Source.User, fixedFee)
+	SetFeeQuoter(t, c.Source.Chain, c.Source.Router, c.Source.User, c.Dest.ChainSelector, quoter)
+	AssertFeeFromQuoter(t, c.Source.Router, c.Dest.ChainSelector, quoter, fixedFee, small, large)
+}
@@ -0,0 +1,186 @@
+package testhelpers
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_onramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/price_registry"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/generated/link_token_interface"
+)
+
+// SetSourceTokenPrice sets the USD price of token on the source price registry as its owner.
+func (c *CCIPContracts) SetSourceTokenPrice(t *testing.T, token common.Address, price *big.Int) {
+	tx, err := c.Source.PriceRegistry.UpdatePrices(c.Source.User, tokenPriceUpdate(token, price))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+}
+
+// SendExpectingZeroFeeRejection misconfigures the fee token of msg with a zero price, which would quote the
+// message at no cost, and asserts that both quoting and sending the message from sender are rejected.
+func (c *CCIPContracts) SendExpectingZeroFeeRejection(t *testing.T, sender *bind.TransactOpts, msg router.ClientEVM2AnyMessage) {
+	c.SetSourceTokenPrice(t, msg.FeeToken, big.NewInt(0))
+
+	_, err := c.Source.Router.GetFee(&bind.CallOpts{From: sender.From}, c.Dest.ChainSelector, msg)
+	AssertRevertedWith(t, err, price_registry.PriceRegistryABI, "TokenNotSupported")
+	_, err = c.Source.Router.CcipSend(sender, c.Dest.ChainSelector, msg)
+	AssertRevertedWith(t, err, price_registry.PriceRegistryABI, "TokenNotSupported")
+}
+
+// AccruedFees returns the fees in feeToken that onRamp has accrued and not yet paid out. LINK fees are tracked
+// as NOP fees by the onRamp, fees in other tokens are its entire balance of that token.
+func AccruedFees(t *testing.T, chain bind.ContractBackend, onRamp *evm_2_evm_onramp.EVM2EVMOnRamp, feeToken common.Address) *big.Int {
+	staticConfig, err := onRamp.GetStaticConfig(nil)
+	require.NoError(t, err)
+	if feeToken == staticConfig.LinkToken {
+		fees, err := onRamp.GetNopFeesJuels(nil)
+		require.NoError(t, err)
+		return fees
+	}
+	return GetBalance(t, chain, feeToken, onRamp.Address())
+}
+
+// WithdrawFeesAssertingAmount withdraws the fees in feeToken accrued by onRamp to to as owner, and asserts that
+// exactly expected was withdrawn and nothing remains accrued. LINK fees can only be paid out to NOPs, so for LINK
+// to is configured as the only NOP of onRamp, which must not have any NOPs configured yet.
+func WithdrawFeesAssertingAmount(t *testing.T, chain *backends.SimulatedBackend, onRamp *evm_2_evm_onramp.EVM2EVMOnRamp, owner *bind.TransactOpts, feeToken common.Address, to common.Address, expected *big.Int) {
+	staticConfig, err := onRamp.GetStaticConfig(nil)
+	require.NoError(t, err)
+	balanceBefore := GetBalance(t, chain, feeToken, to)
+
+	var txs []*types.Transaction
+	if feeToken == staticConfig.LinkToken {
+		nops, err := onRamp.GetNops(nil)
+		require.NoError(t, err)
+		require.Zero(t, nops.WeightsTotal.Sign(), "onRamp already has NOPs configured")
+		tx, err := onRamp.SetNops(owner, []evm_2_evm_onramp.EVM2EVMOnRampNopAndWeight{{Nop: to, Weight: 1}})
+		require.NoError(t, err)
+		txs = append(txs, tx)
+		tx, err = onRamp.PayNops(owner)
+		require.NoError(t, err)
+		txs = append(txs, tx)
+	} else {
+		tx, err := onRamp.WithdrawNonLinkFees(owner, feeToken, to)
+		require.NoError(t, err)
+		txs = append(txs, tx)
+	}
+	ConfirmTxs(t, txs, chain)
+
+	withdrawn := new(big.Int).Sub(GetBalance(t, chain, feeToken, to), balanceBefore)
+	require.Equal(t, expected.String(), withdrawn.String(), "unexpected amount of fees withdrawn")
+	require.Zero(t, AccruedFees(t, chain, onRamp, feeToken).Sign(), "fees still accrued after withdrawal")
+}
+
+// ConfigureFeeTokenWithDecimals makes token, which has the given decimals, a fee token of the lane priced at price,
+// the USD value with 18 decimals of one whole token, as set by ConfigureTokenDecimals. The onRamp charges fees in
+// token with the same fee config as in LINK, so that quotes in either only differ in their denomination.
+func (c *CCIPContracts) ConfigureFeeTokenWithDecimals(t *testing.T, token common.Address, decimals uint8, price *big.Int) {
+	linkConfig, err := c.Source.OnRamp.GetFeeTokenConfig(nil, c.Source.LinkToken.Address())
+	require.NoError(t, err)
+	require.True(t, linkConfig.Enabled, "LINK is not a fee token of the onRamp")
+
+	ConfigureTokenDecimals(t, c.Source.Chain, c.Source.PriceRegistry, c.Source.User, token, decimals, price)
+	tx, err := c.Source.OnRamp.SetFeeTokenConfig(c.Source.User, []evm_2_evm_onramp.EVM2EVMOnRampFeeTokenConfigArgs{{
+		Token:                  token,
+		NetworkFeeUSD:          linkConfig.NetworkFeeUSD,
+		MinTokenTransferFeeUSD: linkConfig.MinTokenTransferFeeUSD,
+		MaxTokenTransferFeeUSD: linkConfig.MaxTokenTransferFeeUSD,
+		GasMultiplier:          linkConfig.GasMultiplier,
+		PremiumMultiplier:      linkConfig.PremiumMultiplier,
+		Enabled:                true,
+	}})
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+}
+
+// AssertFeeCorrectForDecimals asserts that the fee of msg quoted in token, as configured by
+// ConfigureFeeTokenWithDecimals with the given decimals and price, is worth the same in USD as its fee quoted in
+// LINK. Quotes round down to the smallest denomination of their fee token, so their USD values may differ by no
+// more than the value of one such unit of either token. The quote in token is returned.
+func (c *CCIPContracts) AssertFeeCorrectForDecimals(t *testing.T, msg router.ClientEVM2AnyMessage, token common.Address, decimals uint8, price *big.Int) *big.Int {
+	link := c.Source.LinkToken.Address()
+	linkPrice, err := c.Source.PriceRegistry.GetTokenPrice(nil, link)
+	require.NoError(t, err)
+	tokenPrice := registryTokenPrice(price, decimals)
+	onChainPrice, err := c.Source.PriceRegistry.GetTokenPrice(nil, token)
+	require.NoError(t, err)
+	require.Equal(t, tokenPrice.String(), onChainPrice.Value.String(), "price of token is not scaled to %d decimals", decimals)
+
+	quote := func(feeToken common.Address) *big.Int {
+		msg.FeeToken = feeToken
+		fee, err2 := c.Source.Router.GetFee(nil, c.Dest.ChainSelector, msg)
+		require.NoError(t, err2)
+		require.Positive(t, fee.Sign())
+		return fee
+	}
+	linkFee, tokenFee := quote(link), quote(token)
+	// USD values with 36 decimals, as the onRamp computes fees before dividing by the fee token price.
+	linkFeeUSD := new(big.Int).Mul(linkFee, linkPrice.Value)
+	tokenFeeUSD := new(big.Int).Mul(tokenFee, tokenPrice)
+	tolerance := linkPrice.Value
+	if tokenPrice.Cmp(tolerance) > 0 {
+		tolerance = tokenPrice
+	}
+	require.Negative(t, new(big.Int).Abs(new(big.Int).Sub(linkFeeUSD, tokenFeeUSD)).Cmp(tolerance),
+		"fee of %s in token with %d decimals is not worth the fee of %s juels", tokenFee, decimals, linkFee)
+	return tokenFee
+}
+
+// AssertFeeChargedWithoutOverflow sends msg from sender with its fee paid in token, as configured by
+// ConfigureFeeTokenWithDecimals with the given decimals and price, and asserts that the fee is correct for the
+// decimals, as by AssertFeeCorrectForDecimals, and that the onRamp charges exactly the quote. The onRamp keeps the
+// NOP fees of every fee token in juels in a uint96, so they must grow by the LINK value of the fee, converted at
+// the registry prices. sender must hold the fee in token. The fee is returned.
+func (c *CCIPContracts) AssertFeeChargedWithoutOverflow(t *testing.T, sender *bind.TransactOpts, msg router.ClientEVM2AnyMessage, token common.Address, decimals uint8, price *big.Int) *big.Int {
+	fee := c.AssertFeeCorrectForDecimals(t, msg, token, decimals, price)
+	linkPrice, err := c.Source.PriceRegistry.GetTokenPrice(nil, c.Source.LinkToken.Address())
+	require.NoError(t, err)
+	nopFeesBefore, err := c.Source.OnRamp.GetNopFeesJuels(nil)
+	require.NoError(t, err)
+
+	erc20, err := link_token_interface.NewLinkToken(token, c.Source.Chain)
+	require.NoError(t, err)
+	tx, err := erc20.Approve(sender, c.Source.Router.Address(), fee)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	msg.FeeToken = token
+	tx, err = c.Source.Router.CcipSend(sender, c.Dest.ChainSelector, msg)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	receipt, err := c.Source.Chain.TransactionReceipt(context.Background(), tx.Hash())
+	require.NoError(t, err)
+	var charged *big.Int
+	for _, log := range receipt.Logs {
+		if log.Address != c.Source.OnRamp.Address() {
+			continue
+		}
+		if requested, err2 := c.Source.OnRamp.ParseCCIPSendRequested(*log); err2 == nil {
+			charged = requested.Message.FeeTokenAmount
+		}
+	}
+	require.NotNil(t, charged, "send did not emit CCIPSendRequested")
+	require.Equal(t, fee.String(), charged.String(), "onRamp charged a different fee than quoted")
+
+	nopFeesAfter, err := c.Source.OnRamp.GetNopFeesJuels(nil)
+	require.NoError(t, err)
+	juels := new(big.Int).Div(new(big.Int).Mul(fee, registryTokenPrice(price, decimals)), linkPrice.Value)
+	require.Equal(t, juels.String(), new(big.Int).Sub(nopFeesAfter, nopFeesBefore).String(), "NOP fees did not grow by the LINK value of the fee")
+	return fee
+}
+
+// registryTokenPrice returns the price registry price, per 1e18 of its smallest denomination, of a token with the
+// given decimals whose whole tokens are worth price.
+func registryTokenPrice(price *big.Int, decimals uint8) *big.Int {
+	if decimals > 18 {
+		return new(big.Int).Div(price, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals-18)), nil))
+	}
+	return new(big.Int).Mul(price, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(18-decimals)), nil))
+}
@@ -0,0 +1,177 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/shared/generated/burn_mint_erc677"
+)
+
+func TestZeroFeeRejection(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, c.Dest.Receivers[0].Receiver.Address()),
+		Data:         []byte("hello"),
+		TokenAmounts: []router.ClientEVMTokenAmount{},
+		FeeToken:     c.Source.WrappedNative.Address(),
+		ExtraArgs:    extraArgs,
+	}
+
+	// With a correctly priced fee token the message has a non-zero fee.
+	fee, err := c.Source.Router.GetFee(nil, c.Dest.ChainSelector, msg)
+	require.NoError(t, err)
+	require.Positive(t, fee.Sign())
+
+	sourceBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+	c.SendExpectingZeroFeeRejection(t, c.Source.User, msg)
+	require.Empty(t, c.SendRequestedMessages(t, sourceBlock))
+
+	// Other fee tokens are unaffected.
+	tx, err := c.Source.LinkToken.Approve(c.Source.User, c.Source.Router.Address(), HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	msg.FeeToken = c.Source.LinkToken.Address()
+	c.SendRequest(t, msg)
+	require.Len(t, c.SendRequestedMessages(t, sourceBlock), 1)
+}
+
+func TestFeeWithdrawalAccounting(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	sumFees := func(fromBlock uint64, feeToken common.Address) *big.Int {
+		total := big.NewInt(0)
+		for _, msg := range c.SendRequestedMessages(t, fromBlock) {
+			require.Equal(t, feeToken, msg.FeeToken)
+			total.Add(total, msg.FeeTokenAmount)
+		}
+		return total
+	}
+	recipient := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	t.Run("link", func(t *testing.T) {
+		linkToken := c.Source.LinkToken.Address()
+		sourceBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+		c.SendDataMessages(t, 3, big.NewInt(200_000))
+		charged := sumFees(sourceBlock, linkToken)
+		require.Positive(t, charged.Sign())
+		require.Equal(t, charged.String(), AccruedFees(t, c.Source.Chain, c.Source.OnRamp, linkToken).String())
+
+		WithdrawFeesAssertingAmount(t, c.Source.Chain, c.Source.OnRamp, c.Source.User, linkToken, recipient, charged)
+	})
+
+	t.Run("non-link", func(t *testing.T) {
+		weth := c.Source.WrappedNative.Address()
+		c.Source.User.Value = HundredLink
+		tx, err := c.Source.WrappedNative.Deposit(c.Source.User)
+		c.Source.User.Value = nil
+		require.NoError(t, err)
+		ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+		tx, err = c.Source.WrappedNative.Approve(c.Source.User, c.Source.Router.Address(), HundredLink)
+		require.NoError(t, err)
+		ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+
+		extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+		require.NoError(t, err)
+		sourceBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+		for i := 0; i < 2; i++ {
+			c.SendRequest(t, router.ClientEVM2AnyMessage{
+				Receiver:     MustEncodeAddress(t, c.Dest.Receivers[0].Receiver.Address()),
+				Data:         []byte("hello"),
+				TokenAmounts: []router.ClientEVMTokenAmount{},
+				FeeToken:     weth,
+				ExtraArgs:    extraArgs,
+			})
+		}
+		charged := sumFees(sourceBlock, weth)
+		require.Positive(t, charged.Sign())
+		require.Equal(t, charged.String(), AccruedFees(t, c.Source.Chain, c.Source.OnRamp, weth).String())
+
+		// Non-LINK fees are owed to NOPs in LINK, which has to be topped up before they can be withdrawn.
+		linkAvailable, err := c.Source.OnRamp.LinkAvailableForPayment(nil)
+		require.NoError(t, err)
+		require.Negative(t, linkAvailable.Sign())
+		tx, err = c.Source.LinkToken.Transfer(c.Source.User, c.Source.OnRamp.Address(), new(big.Int).Neg(linkAvailable))
+		require.NoError(t, err)
+		ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+
+		WithdrawFeesAssertingAmount(t, c.Source.Chain, c.Source.OnRamp, c.Source.User, weth, recipient, charged)
+	})
+}
+
+func TestFeeCorrectForDecimals(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	deployToken := func(symbol string, decimals uint8) common.Address {
+		token, tx, _, err := burn_mint_erc677.DeployBurnMintERC677(c.Source.User, c.Source.Chain, symbol, symbol, decimals, big.NewInt(0))
+		require.NoError(t, err)
+		ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+		return token
+	}
+	// Two dollar stablecoins which only differ in their decimals.
+	usd6, usd18 := deployToken("USD6", 6), deployToken("USD18", 18)
+	oneDollar := big.NewInt(1e18)
+	c.ConfigureFeeTokenWithDecimals(t, usd6, 6, oneDollar)
+	c.ConfigureFeeTokenWithDecimals(t, usd18, 18, oneDollar)
+
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, c.Dest.Receivers[0].Receiver.Address()),
+		Data:         []byte("hello"),
+		TokenAmounts: []router.ClientEVMTokenAmount{},
+		ExtraArgs:    extraArgs,
+	}
+	fee6 := c.AssertFeeCorrectForDecimals(t, msg, usd6, 6, oneDollar)
+	fee18 := c.AssertFeeCorrectForDecimals(t, msg, usd18, 18, oneDollar)
+	// Both quotes round down the same USD fee, the 6 decimal one at a coarser denomination.
+	require.Equal(t, new(big.Int).Div(fee18, big.NewInt(1e12)).String(), fee6.String())
+}
+
+func TestFeeWithExtremeDecimals(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, c.Dest.Receivers[0].Receiver.Address()),
+		Data:         []byte("hello"),
+		TokenAmounts: []router.ClientEVMTokenAmount{},
+		ExtraArgs:    extraArgs,
+	}
+
+	for _, tc := range []struct {
+		name     string
+		decimals uint8
+		// price of one whole token, chosen so that the fee is at least one unit of the token.
+		price *big.Int
+	}{
+		// Priced up by 1e18 to 1e32 per 1e18 units, each unit worth a hundredth of a cent.
+		{"0 decimals", 0, big.NewInt(1e14)},
+		// Priced down by 1e18 to 1000 per 1e18 units.
+		{"36 decimals", 36, new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			address, tx, _, err := burn_mint_erc677.DeployBurnMintERC677(c.Source.User, c.Source.Chain, tc.name, tc.name, tc.decimals, big.NewInt(0))
+			require.NoError(t, err)
+			ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+			token, err := burn_mint_erc677.NewBurnMintERC677(address, c.Source.Chain)
+			require.NoError(t, err)
+			tx, err = token.GrantMintRole(c.Source.User, c.Source.User.From)
+			require.NoError(t, err)
+			ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+			// A million whole tokens, which is 1e42 units of the 36 decimal token.
+			supply := new(big.Int).Mul(big.NewInt(1e6), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(tc.decimals)), nil))
+			tx, err = token.Mint(c.Source.User, c.Source.User.From, supply)
+			require.NoError(t, err)
+			ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+
+			c.ConfigureFeeTokenWithDecimals(t, address, tc.decimals, tc.price)
+			fee := c.AssertFeeChargedWithoutOverflow(t, c.Source.User, msg, address, tc.decimals, tc.price)
+			require.Positive(t, fee.Sign())
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package testhelpers
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// FinalityTagBackend wraps a simulated backend, which has no notion of finality, and answers queries for the
+// finalized block tag with the block a settable depth behind head. Other queries are passed through.
+type FinalityTagBackend struct {
+	*backends.SimulatedBackend
+	finalizedDepth atomic.Uint64
+}
+
+// NewFinalityTagBackend wraps chain, treating blocks finalizedDepth behind head as finalized.
+func NewFinalityTagBackend(chain *backends.SimulatedBackend, finalizedDepth uint64) *FinalityTagBackend {
+	b := &FinalityTagBackend{SimulatedBackend: chain}
+	b.SetFinalizedDepth(finalizedDepth)
+	return b
+}
+
+// SetFinalizedDepth sets how many blocks behind head the finalized block is.
+func (b *FinalityTagBackend) SetFinalizedDepth(n uint64) {
+	b.finalizedDepth.Store(n)
+}
+
+// FinalizedBlockNumber returns the number of the block currently considered finalized.
+func (b *FinalityTagBackend) FinalizedBlockNumber() *big.Int {
+	head := b.Blockchain().CurrentBlock().Number.Uint64()
+	depth := b.finalizedDepth.Load()
+	if depth > head {
+		return big.NewInt(0)
+	}
+	return new(big.Int).SetUint64(head - depth)
+}
+
+func (b *FinalityTagBackend) resolve(number *big.Int) *big.Int {
+	if number != nil && number.Int64() == rpc.FinalizedBlockNumber.Int64() {
+		return b.FinalizedBlockNumber()
+	}
+	return number
+}
+
+// BlockByNumber returns the block with the given number, resolving the finalized block tag.
+func (b *FinalityTagBackend) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return b.SimulatedBackend.BlockByNumber(ctx, b.resolve(number))
+}
+
+// HeaderByNumber returns the header with the given number, resolving the finalized block tag.
+func (b *FinalityTagBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return b.SimulatedBackend.HeaderByNumber(ctx, b.resolve(number))
+}
+
+// AssertOnlyFinalizedMessagesCommitted sends a data message and asserts that the lane only commits it once its block
+// is finalityDepth blocks behind the source head. commitRound drives a commit round of the lane, committing whatever
+// the commit plugin observes. It is run once with the message one block short of finality, when the message must not
+// be committed, and once more after another source block, when it must be.
+func (c *CCIPContracts) AssertOnlyFinalizedMessagesCommitted(t *testing.T, finalityDepth uint64, commitRound func(t *testing.T)) {
+	require.Positive(t, finalityDepth)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	c.SendDataMessages(t, 1, big.NewInt(100_000))
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 1)
+	seqNum := msgs[0].SequenceNumber
+
+	// The message is in the head block, so it is finalized once finalityDepth more blocks are mined.
+	for i := uint64(1); i < finalityDepth; i++ {
+		c.Source.Chain.Commit()
+	}
+	commitRound(t)
+	require.False(t, c.isCommitted(t, seqNum), "seqNum %d was committed one block short of finality", seqNum)
+
+	c.Source.Chain.Commit()
+	commitRound(t)
+	require.True(t, c.isCommitted(t, seqNum), "seqNum %d was not committed once finalized", seqNum)
+}
+
+// isCommitted returns whether a root covering seqNum was accepted by the commitStore.
+func (c *CCIPContracts) isCommitted(t *testing.T, seqNum uint64) bool {
+	it, err := c.Dest.CommitStore.FilterReportAccepted(&bind.FilterOpts{})
+	require.NoError(t, err)
+	defer it.Close()
+	var committed bool
+	for it.Next() {
+		interval := it.Event.Report.Interval
+		committed = committed || (interval.Min <= seqNum && seqNum <= interval.Max)
+	}
+	require.NoError(t, it.Error())
+	next, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, committed, next > seqNum, "commitStore expects seqNum %d next", next)
+	return committed
+}
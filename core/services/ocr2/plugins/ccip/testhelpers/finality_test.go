@@ -0,0 +1,42 @@
+package testhelpers
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinalityTagBackend(t *testing.T) {
+	chain, _ := SetupChain(t)
+	backend := NewFinalityTagBackend(chain, 3)
+	finalizedTag := big.NewInt(rpc.FinalizedBlockNumber.Int64())
+
+	// The finalized block cannot be before genesis.
+	block, err := backend.BlockByNumber(context.Background(), finalizedTag)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), block.NumberU64())
+
+	for i := 0; i < 10; i++ {
+		chain.Commit()
+	}
+	head := chain.Blockchain().CurrentBlock().Number.Uint64()
+	block, err = backend.BlockByNumber(context.Background(), finalizedTag)
+	require.NoError(t, err)
+	require.Equal(t, head-3, block.NumberU64())
+	header, err := backend.HeaderByNumber(context.Background(), finalizedTag)
+	require.NoError(t, err)
+	require.Equal(t, block.Hash(), header.Hash())
+
+	backend.SetFinalizedDepth(5)
+	block, err = backend.BlockByNumber(context.Background(), finalizedTag)
+	require.NoError(t, err)
+	require.Equal(t, head-5, block.NumberU64())
+
+	// Other queries are passed through.
+	block, err = backend.BlockByNumber(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, head, block.NumberU64())
+}
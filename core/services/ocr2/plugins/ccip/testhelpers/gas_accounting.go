@@ -0,0 +1,57 @@
+package testhelpers
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// AssertExecutionGasAccounting asserts that the successful execution in receipt used no more than expectedMax
+// gas, and that its transmitter paid exactly for the gas used at the effective gas price of the transaction.
+// The offRamp does not reimburse transmitters, so any increase over that, such as a refund of over-estimated
+// gas credited to the transmitter or the offRamp, is an accounting bug, as is any charge beyond it. The balances
+// are compared across the block of the execution, in which the transmitter must not have sent anything else.
+func (c *CCIPContracts) AssertExecutionGasAccounting(t *testing.T, receipt *types.Receipt, expectedMax uint64) {
+	ctx := context.Background()
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, "execution reverted")
+	require.Positive(t, receipt.GasUsed)
+	require.LessOrEqual(t, receipt.GasUsed, expectedMax, "execution used more gas than expected")
+
+	block, err := c.Dest.Chain.BlockByHash(ctx, receipt.BlockHash)
+	require.NoError(t, err)
+	signer := types.LatestSignerForChainID(c.Dest.Chain.Blockchain().Config().ChainID)
+	var tx *types.Transaction
+	for _, blockTx := range block.Transactions() {
+		if blockTx.Hash() == receipt.TxHash {
+			tx = blockTx
+		}
+	}
+	require.NotNil(t, tx, "execution not found in its block")
+	require.True(t, tx.To() != nil && *tx.To() == c.Dest.OffRamp.Address(), "receipt is not of an execution")
+	transmitter, err := types.Sender(signer, tx)
+	require.NoError(t, err)
+	for _, blockTx := range block.Transactions() {
+		if blockTx.Hash() == tx.Hash() {
+			continue
+		}
+		from, err2 := types.Sender(signer, blockTx)
+		require.NoError(t, err2)
+		require.NotEqual(t, transmitter, from, "transmitter sent %s in the block of the execution", blockTx.Hash())
+	}
+
+	balanceDelta := func(account common.Address) *big.Int {
+		before, err2 := c.Dest.Chain.BalanceAt(ctx, account, new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1)))
+		require.NoError(t, err2)
+		after, err2 := c.Dest.Chain.BalanceAt(ctx, account, receipt.BlockNumber)
+		require.NoError(t, err2)
+		return new(big.Int).Sub(after, before)
+	}
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	require.Equal(t, new(big.Int).Neg(cost).String(), balanceDelta(transmitter).String(),
+		"transmitter was charged something else than the %d gas used at %s wei", receipt.GasUsed, receipt.EffectiveGasPrice)
+	require.Zero(t, balanceDelta(c.Dest.OffRamp.Address()).Sign(), "execution moved native funds of the offRamp")
+}
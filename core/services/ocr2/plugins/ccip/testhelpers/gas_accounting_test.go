@@ -0,0 +1,28 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestExecutionGasAccounting(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	// The message gas limit far exceeds what the receiver needs, so most of it goes unused.
+	gasLimit := big.NewInt(1_000_000)
+	c.SendDataMessages(t, 1, gasLimit)
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 1)
+	tree := c.CommitMessages(t, msgs)
+
+	receipt, err := c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0}))
+	require.NoError(t, err)
+	c.AssertExecStateForSeqNum(t, msgs[0].SequenceNumber, abihelpers.ExecutionStateSuccess)
+	require.Less(t, receipt.GasUsed, gasLimit.Uint64())
+	c.AssertExecutionGasAccounting(t, receipt, gasLimit.Uint64())
+}
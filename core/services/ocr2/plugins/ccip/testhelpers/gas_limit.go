@@ -0,0 +1,48 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_onramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+// gasLimitMessage returns a data-only message to sender with V1 extraArgs carrying gasLimit, paid for in native tokens.
+func gasLimitMessage(t *testing.T, sender *bind.TransactOpts, gasLimit *big.Int) router.ClientEVM2AnyMessage {
+	return router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, sender.From),
+		Data:         []byte("hello"),
+		TokenAmounts: []router.ClientEVMTokenAmount{},
+		FeeToken:     common.Address{},
+		ExtraArgs:    EncodeExtraArgsVersion(t, 1, gasLimit),
+	}
+}
+
+// SendWithGasLimit sends a data-only message to sender on the chain with destSelector through r, requesting gasLimit
+// for its execution and paying the fee in native tokens. The fee quote must succeed, and errors returned by the
+// router for the send are passed to the caller. A returned transaction is not yet mined.
+func SendWithGasLimit(t *testing.T, r *router.Router, sender *bind.TransactOpts, destSelector uint64, gasLimit *big.Int) (*types.Transaction, error) {
+	msg := gasLimitMessage(t, sender, gasLimit)
+	fee, err := r.GetFee(&bind.CallOpts{From: sender.From}, destSelector, msg)
+	require.NoError(t, err)
+	opts := *sender
+	opts.Value = fee
+	return r.CcipSend(&opts, destSelector, msg)
+}
+
+// SendWithGasLimitExpectingReject asserts that the onRamp of the lane to destSelector rejects a message requesting
+// gasLimit, which must exceed the MaxGasLimit of the lane, with MessageGasLimitTooHigh. Both the fee quote and the
+// send through r are rejected, so the send attaches no fee.
+func SendWithGasLimitExpectingReject(t *testing.T, r *router.Router, sender *bind.TransactOpts, destSelector uint64, gasLimit *big.Int) {
+	msg := gasLimitMessage(t, sender, gasLimit)
+	_, err := r.GetFee(&bind.CallOpts{From: sender.From}, destSelector, msg)
+	AssertRevertedWith(t, err, evm_2_evm_onramp.EVM2EVMOnRampABI, "MessageGasLimitTooHigh")
+	_, err = r.CcipSend(sender, destSelector, msg)
+	AssertRevertedWith(t, err, evm_2_evm_onramp.EVM2EVMOnRampABI, "MessageGasLimitTooHigh")
+}
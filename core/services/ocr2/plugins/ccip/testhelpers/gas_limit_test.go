@@ -0,0 +1,23 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendWithGasLimitExpectingReject(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	dynamicConfig, err := c.Source.OnRamp.GetDynamicConfig(nil)
+	require.NoError(t, err)
+	maxGasLimit := new(big.Int).SetUint64(uint64(dynamicConfig.MaxGasLimit))
+
+	// The cap itself is accepted.
+	tx, err := SendWithGasLimit(t, c.Source.Router, c.Source.User, c.Dest.ChainSelector, maxGasLimit)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+
+	SendWithGasLimitExpectingReject(t, c.Source.Router, c.Source.User, c.Dest.ChainSelector, new(big.Int).Add(maxGasLimit, big.NewInt(1)))
+}
@@ -0,0 +1,45 @@
+package testhelpers
+
+import (
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// GasSample is the source gas price an oracle of the commit DON observes in a round, along with the USD price of
+// the source native token, with 18 decimals, at which the oracle converts it to USD.
+type GasSample struct {
+	GasPriceWei    *big.Int
+	NativePriceUSD *big.Int
+}
+
+// UsdPerUnitGas returns the USD price of a unit of gas, with 18 decimals, of the sample.
+func (s GasSample) UsdPerUnitGas() *big.Int {
+	usd := new(big.Int).Mul(s.GasPriceWei, s.NativePriceUSD)
+	return usd.Div(usd, big.NewInt(1e18))
+}
+
+// AggregateGasPrice returns the gas price a commit report carries for samples, the observations of a DON
+// tolerating f faulty oracles. Oracles observe a gas price each and are not weighted: the report carries the
+// median of the USD prices of the samples, the upper middle one for an even number of samples, and zero, which
+// skips the update, unless more than f oracles observed a price. Prices already on chain are not considered.
+func AggregateGasPrice(samples []GasSample, f int) *big.Int {
+	if len(samples) <= f {
+		return big.NewInt(0)
+	}
+	prices := make([]*big.Int, len(samples))
+	for i, sample := range samples {
+		prices[i] = sample.UsdPerUnitGas()
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Cmp(prices[j]) < 0 })
+	return prices[len(prices)/2]
+}
+
+// AssertCommittedGasPrice asserts that committed, the gas price reported by the commit plugin for samples as the
+// observations of a DON tolerating f faulty oracles, is the one given by AggregateGasPrice.
+func AssertCommittedGasPrice(t *testing.T, samples []GasSample, f int, committed *big.Int) {
+	require.NotNil(t, committed)
+	require.Equal(t, AggregateGasPrice(samples, f).String(), committed.String(), "unexpected gas price committed for %d samples", len(samples))
+}
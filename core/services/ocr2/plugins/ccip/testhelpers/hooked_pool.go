@@ -0,0 +1,258 @@
+package testhelpers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/lock_release_token_pool"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// hookedPoolABI covers the functions of a hooked pool beyond releaseOrMint, along with the error its hook reverts
+// with.
+const hookedPoolABI = `[
+	{"type":"function","name":"getToken","inputs":[],"outputs":[{"name":"","type":"address"}],"stateMutability":"view"},
+	{"type":"function","name":"setHook","inputs":[{"name":"hook","type":"uint8"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"getHook","inputs":[],"outputs":[{"name":"","type":"uint8"}],"stateMutability":"view"},
+	{"type":"error","name":"HookReverted","inputs":[{"name":"hook","type":"uint8"}]}
+]`
+
+// hookedPoolRuntime is the assembly of a hooked pool, which keeps its hook in slot 0 and releases with a transfer of
+// its token. %[1]s to %[4]s are the getToken, releaseOrMint, setHook and getHook selectors, %[5]s the HookReverted
+// selector, %[6]s the token and %[7]s the owner.
+const hookedPoolRuntime = `
+PUSH 0
+CALLDATALOAD
+PUSH 224
+SHR
+DUP1
+PUSH %[1]s
+EQ
+JUMPI @getToken
+DUP1
+PUSH %[2]s
+EQ
+JUMPI @releaseOrMint
+DUP1
+PUSH %[3]s
+EQ
+JUMPI @setHook
+DUP1
+PUSH %[4]s
+EQ
+JUMPI @getHook
+JUMP @fail
+
+getToken:
+PUSH %[6]s
+JUMP @returnWord
+
+getHook:
+PUSH 0
+SLOAD
+JUMP @returnWord
+
+setHook:
+PUSH %[7]s
+CALLER
+EQ
+ISZERO
+JUMPI @fail
+PUSH 4
+CALLDATALOAD
+PUSH 0
+SSTORE
+STOP
+
+;; calldata: originalSender offset, receiver, amount, sourceChainSelector, extraData offset
+releaseOrMint:
+PUSH 1
+PUSH 0
+SLOAD
+EQ
+JUMPI @hookReverted
+PUSH 0xa9059cbb
+PUSH 224
+SHL
+PUSH 0
+MSTORE
+PUSH 36
+CALLDATALOAD
+PUSH 4
+MSTORE
+PUSH 68
+CALLDATALOAD
+PUSH 36
+MSTORE
+PUSH 32
+PUSH 0
+PUSH 68
+PUSH 0
+PUSH 0
+PUSH %[6]s
+GAS
+CALL
+ISZERO
+JUMPI @fail
+PUSH 0
+MLOAD
+ISZERO
+JUMPI @fail
+PUSH 2
+PUSH 0
+SLOAD
+EQ
+JUMPI @hookReverted
+STOP
+
+hookReverted:
+PUSH %[5]s
+PUSH 224
+SHL
+PUSH 0
+MSTORE
+PUSH 0
+SLOAD
+PUSH 4
+MSTORE
+PUSH 36
+PUSH 0
+REVERT
+
+returnWord:
+PUSH 0
+MSTORE
+PUSH 32
+PUSH 0
+RETURN
+
+fail:
+PUSH 0
+DUP1
+REVERT
+`
+
+// PoolHook is the hook a hooked pool runs around its releases.
+type PoolHook uint8
+
+const (
+	// PoolHookNone lets releases through.
+	PoolHookNone PoolHook = iota
+	// PoolHookRevertBeforeTransfer reverts releases before the pool moves any tokens.
+	PoolHookRevertBeforeTransfer
+	// PoolHookRevertAfterTransfer reverts releases after the pool transferred the tokens to the receiver.
+	PoolHookRevertAfterTransfer
+)
+
+// HookedPool is a release-only token pool that runs a hook, which its owner can set to revert, around every
+// release. It has neither ramps nor rate limits, and releases its tokens to any caller, so it is only fit for
+// simulated chains.
+type HookedPool struct {
+	contract *bind.BoundContract
+}
+
+// SetHook sets the hook of the pool, which only its owner may do.
+func (p *HookedPool) SetHook(opts *bind.TransactOpts, hook PoolHook) (*types.Transaction, error) {
+	return p.contract.Transact(opts, "setHook", uint8(hook))
+}
+
+// Hook returns the hook of the pool.
+func (p *HookedPool) Hook(opts *bind.CallOpts) (PoolHook, error) {
+	var out []interface{}
+	if err := p.contract.Call(opts, &out, "getHook"); err != nil {
+		return 0, err
+	}
+	return PoolHook(*abi.ConvertType(out[0], new(uint8)).(*uint8)), nil
+}
+
+// DeployHookedPool deploys a hooked pool of token owned by owner, without a hook. The pool releases from its own
+// balance of token, which must be funded before any release.
+func DeployHookedPool(t *testing.T, chain *backends.SimulatedBackend, owner *bind.TransactOpts, token common.Address) (*HookedPool, common.Address) {
+	poolABI, err := abi.JSON(strings.NewReader(hookedPoolABI))
+	require.NoError(t, err)
+	lockReleaseABI, err := lock_release_token_pool.LockReleaseTokenPoolMetaData.GetAbi()
+	require.NoError(t, err)
+	runtime := assemble(t, fmt.Sprintf(hookedPoolRuntime,
+		hexutil.Encode(poolABI.Methods["getToken"].ID), hexutil.Encode(lockReleaseABI.Methods["releaseOrMint"].ID),
+		hexutil.Encode(poolABI.Methods["setHook"].ID), hexutil.Encode(poolABI.Methods["getHook"].ID),
+		hexutil.Encode(poolABI.Errors["HookReverted"].ID.Bytes()[:4]), token.Hex(), owner.From.Hex()))
+
+	address, tx, contract, err := bind.DeployContract(owner, poolABI, creationCode(runtime), chain)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+	code, err := chain.CodeAt(context.Background(), address, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, code)
+	return &HookedPool{contract: contract}, address
+}
+
+// ReleaseSourceLinkThrough makes the offRamp release source LINK through pool, a pool of dest LINK, in place of the
+// dest LINK pool, and funds pool with liquidity of dest LINK from the dest user.
+func (c *CCIPContracts) ReleaseSourceLinkThrough(t *testing.T, pool common.Address, liquidity *big.Int) {
+	sourceLink := c.Source.LinkToken.Address()
+	tx1, err := c.Dest.OffRamp.ApplyPoolUpdates(c.Dest.User,
+		[]evm_2_evm_offramp.InternalPoolUpdate{{Token: sourceLink, Pool: c.Dest.Pool.Address()}},
+		[]evm_2_evm_offramp.InternalPoolUpdate{{Token: sourceLink, Pool: pool}},
+	)
+	require.NoError(t, err)
+	tx2, err := c.Dest.LinkToken.Transfer(c.Dest.User, pool, liquidity)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx1, tx2}, c.Dest.Chain)
+}
+
+// ExecuteExpectingHookRevert transmits report, which must execute a single token transfer to receiver through pool
+// with its hook set to revert, and asserts that the message fails gracefully: the report is accepted, but the
+// message is marked as failed with a TokenHandlingError wrapping the HookReverted revert of the pool, and neither
+// the balance of pool nor that of receiver changes, even if the hook reverts after the transfer.
+func (c *CCIPContracts) ExecuteExpectingHookRevert(t *testing.T, oracle SimulatedOracle, report evm_2_evm_offramp.InternalExecutionReport, pool *HookedPool, poolAddress, receiver common.Address) {
+	require.Len(t, report.Messages, 1)
+	seqNum := report.Messages[0].SequenceNumber
+	hook, err := pool.Hook(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, PoolHookNone, hook, "hook of the pool does not revert")
+	token := c.Dest.LinkToken.Address()
+	poolBefore := GetBalance(t, c.Dest.Chain, token, poolAddress)
+	receiverBefore := GetBalance(t, c.Dest.Chain, token, receiver)
+
+	receipt, err := c.TransmitExecutionReport(t, oracle, report)
+	require.NoError(t, err)
+	var returnData []byte
+	for _, log := range receipt.Logs {
+		if log.Address != c.Dest.OffRamp.Address() {
+			continue
+		}
+		stateChanged, err := c.Dest.OffRamp.ParseExecutionStateChanged(*log)
+		if err == nil && stateChanged.SequenceNumber == seqNum {
+			returnData = stateChanged.ReturnData
+		}
+	}
+	c.AssertExecStateForSeqNum(t, seqNum, abihelpers.ExecutionStateFailure)
+
+	AssertErrorSelector(t, returnData, evm_2_evm_offramp.EVM2EVMOffRampABI, "TokenHandlingError")
+	offRampABI, err := evm_2_evm_offramp.EVM2EVMOffRampMetaData.GetAbi()
+	require.NoError(t, err)
+	unpacked, err := offRampABI.Errors["TokenHandlingError"].Inputs.Unpack(returnData[4:])
+	require.NoError(t, err)
+	poolError := unpacked[0].([]byte)
+	AssertErrorSelector(t, poolError, hookedPoolABI, "HookReverted")
+	poolABI, err := abi.JSON(strings.NewReader(hookedPoolABI))
+	require.NoError(t, err)
+	args, err := poolABI.Errors["HookReverted"].Inputs.Unpack(poolError[4:])
+	require.NoError(t, err)
+	require.Equal(t, uint8(hook), args[0])
+
+	require.Equal(t, poolBefore.String(), GetBalance(t, c.Dest.Chain, token, poolAddress).String(), "pool balance changed")
+	require.Equal(t, receiverBefore.String(), GetBalance(t, c.Dest.Chain, token, receiver).String(), "receiver balance changed")
+}
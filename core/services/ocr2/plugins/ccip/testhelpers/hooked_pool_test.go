@@ -0,0 +1,64 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestHookedPool(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	pool, poolAddress := DeployHookedPool(t, c.Dest.Chain, c.Dest.User, c.Dest.LinkToken.Address())
+	c.ReleaseSourceLinkThrough(t, poolAddress, Link(10))
+	receiver := common.HexToAddress("0x6666666666666666666666666666666666666666")
+
+	// Only the owner can set the hook.
+	_, err := pool.SetHook(NewFundedUser(t, c.Dest.Chain, c.Dest.User), PoolHookRevertBeforeTransfer)
+	require.Error(t, err)
+
+	tx, err := c.Source.LinkToken.Approve(c.Source.User, c.Source.Router.Address(), HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	for i := 0; i < 2; i++ {
+		c.SendRequest(t, router.ClientEVM2AnyMessage{
+			Receiver:     MustEncodeAddress(t, receiver),
+			Data:         []byte{},
+			TokenAmounts: []router.ClientEVMTokenAmount{{Token: c.Source.LinkToken.Address(), Amount: Link(1)}},
+			FeeToken:     c.Source.LinkToken.Address(),
+			ExtraArgs:    extraArgs,
+		})
+	}
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 2)
+	tree := c.CommitMessages(t, msgs)
+
+	for i, hook := range []PoolHook{PoolHookRevertBeforeTransfer, PoolHookRevertAfterTransfer} {
+		tx, err = pool.SetHook(c.Dest.User, hook)
+		require.NoError(t, err)
+		ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+		c.ExecuteExpectingHookRevert(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{i}), pool, poolAddress, receiver)
+	}
+
+	// Once the hook lets releases through, the failed messages can be manually executed.
+	tx, err = pool.SetHook(c.Dest.User, PoolHookNone)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	_, err = c.ManuallyExecuteAt(t, BuildExecutionReport(t, tree, msgs, []int{0, 1}), ChainClock(c.Dest.Chain).Add(time.Minute))
+	require.NoError(t, err)
+	for _, msg := range msgs {
+		c.AssertExecStateForSeqNum(t, msg.SequenceNumber, abihelpers.ExecutionStateSuccess)
+	}
+	require.Equal(t, Link(2).String(), GetBalance(t, c.Dest.Chain, c.Dest.LinkToken.Address(), receiver).String())
+	require.Equal(t, Link(8).String(), GetBalance(t, c.Dest.Chain, c.Dest.LinkToken.Address(), poolAddress).String())
+}
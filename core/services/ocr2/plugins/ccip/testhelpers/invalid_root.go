@@ -0,0 +1,46 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/commit_store"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/commit_store_helper"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// postZeroRootReport posts a commit report without price updates over interval with a zero root through commitStore.
+func postZeroRootReport(t *testing.T, commitStore *commit_store_helper.CommitStoreHelper, owner *bind.TransactOpts, interval commit_store.CommitStoreInterval) error {
+	report, err := abihelpers.EncodeCommitReport(commit_store.CommitStoreCommitReport{
+		PriceUpdates: commit_store.InternalPriceUpdates{
+			TokenPriceUpdates: []commit_store.InternalTokenPriceUpdate{},
+			UsdPerUnitGas:     big.NewInt(0),
+		},
+		Interval:   interval,
+		MerkleRoot: [32]byte{},
+	})
+	require.NoError(t, err)
+	_, err = commitStore.Report(owner, report, big.NewInt(1))
+	return err
+}
+
+// PostCommitWithInvalidRootExpectingReject asserts that commitStore rejects commit reports with a zero root, posted
+// by owner through the helper. A report without price updates must carry a root, so a zero root over the next
+// sequence number reverts with InvalidRoot, while a zero root over an interval skipping the next sequence number
+// already fails the interval check with InvalidInterval. Neither report advances the commitStore.
+func PostCommitWithInvalidRootExpectingReject(t *testing.T, commitStore *commit_store_helper.CommitStoreHelper, owner *bind.TransactOpts) {
+	next, err := commitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+
+	err = postZeroRootReport(t, commitStore, owner, commit_store.CommitStoreInterval{Min: next, Max: next})
+	AssertRevertedWith(t, err, commit_store.CommitStoreABI, "InvalidRoot")
+	err = postZeroRootReport(t, commitStore, owner, commit_store.CommitStoreInterval{Min: next + 1, Max: next + 1})
+	AssertRevertedWith(t, err, commit_store.CommitStoreABI, "InvalidInterval")
+
+	after, err := commitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, next, after, "rejected reports advanced the commitStore")
+}
@@ -0,0 +1,27 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostCommitWithInvalidRootExpectingReject(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	c.SendDataMessages(t, 1, big.NewInt(100_000))
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 1)
+
+	PostCommitWithInvalidRootExpectingReject(t, c.Dest.CommitStoreHelper, c.Dest.User)
+
+	// A valid root over the same sequence number is still accepted.
+	tree := c.CommitMessages(t, msgs)
+	timestamp, err := c.Dest.CommitStore.GetMerkleRoot(nil, tree.Root())
+	require.NoError(t, err)
+	require.Positive(t, timestamp.Sign())
+	next, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, msgs[0].SequenceNumber+1, next)
+}
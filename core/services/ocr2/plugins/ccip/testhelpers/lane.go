@@ -0,0 +1,144 @@
+package testhelpers
+
+import (
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/commit_store"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/hashlib"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/merklemulti"
+)
+
+// SendDataMessages sends n data-only messages with the given gas limit from the lane owner to the first dest
+// receiver, paying fees in LINK.
+func (c *CCIPContracts) SendDataMessages(t *testing.T, n int, gasLimit *big.Int) {
+	tx, err := c.Source.LinkToken.Approve(c.Source.User, c.Source.Router.Address(), HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	extraArgs, err := GetEVMExtraArgsV1(gasLimit, false)
+	require.NoError(t, err)
+	for i := 0; i < n; i++ {
+		c.SendRequest(t, router.ClientEVM2AnyMessage{
+			Receiver:     MustEncodeAddress(t, c.Dest.Receivers[0].Receiver.Address()),
+			Data:         []byte("hello"),
+			TokenAmounts: []router.ClientEVMTokenAmount{},
+			FeeToken:     c.Source.LinkToken.Address(),
+			ExtraArgs:    extraArgs,
+		})
+	}
+}
+
+// SendRequestedMessages returns the messages sent through the onRamp since fromBlock, as seen by the offRamp.
+func (c *CCIPContracts) SendRequestedMessages(t *testing.T, fromBlock uint64) []evm_2_evm_offramp.InternalEVM2EVMMessage {
+	it, err := c.Source.OnRamp.FilterCCIPSendRequested(&bind.FilterOpts{Start: fromBlock})
+	require.NoError(t, err)
+	defer it.Close()
+	var msgs []evm_2_evm_offramp.InternalEVM2EVMMessage
+	for it.Next() {
+		msgs = append(msgs, abihelpers.OnRampMessageToOffRampMessage(it.Event.Message))
+	}
+	require.NoError(t, it.Error())
+	return msgs
+}
+
+// CommitMessages commits a merkle root over msgs, which must be sent in consecutive sequence numbers starting
+// at the next sequence number expected by the commitStore. The report is posted through the commitStore helper,
+// bypassing OCR, and carries no price updates. The tree is returned so that the messages can be proven.
+func (c *CCIPContracts) CommitMessages(t *testing.T, msgs []evm_2_evm_offramp.InternalEVM2EVMMessage) *merklemulti.Tree[[32]byte] {
+	report, tree := encodeMessagesCommitReport(t, msgs)
+	tx, err := c.Dest.CommitStoreHelper.Report(c.Dest.User, report, big.NewInt(1))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	return tree
+}
+
+// encodeMessagesCommitReport encodes a commit report without price updates over msgs, which must have
+// consecutive sequence numbers, and returns it along with the tree of its merkle root.
+func encodeMessagesCommitReport(t *testing.T, msgs []evm_2_evm_offramp.InternalEVM2EVMMessage) ([]byte, *merklemulti.Tree[[32]byte]) {
+	require.NotEmpty(t, msgs)
+	for i, msg := range msgs {
+		require.Equal(t, msgs[0].SequenceNumber+uint64(i), msg.SequenceNumber, "messages must have consecutive sequence numbers")
+	}
+	tree, _ := BuildSortedTree(t, msgs)
+
+	report, err := abihelpers.EncodeCommitReport(commit_store.CommitStoreCommitReport{
+		PriceUpdates: commit_store.InternalPriceUpdates{
+			TokenPriceUpdates: []commit_store.InternalTokenPriceUpdate{},
+			UsdPerUnitGas:     big.NewInt(0),
+		},
+		Interval:   commit_store.CommitStoreInterval{Min: msgs[0].SequenceNumber, Max: msgs[len(msgs)-1].SequenceNumber},
+		MerkleRoot: tree.Root(),
+	})
+	require.NoError(t, err)
+	return report, tree
+}
+
+// AssertLeavesSorted asserts that msgs are in strictly increasing sequence number order,
+// the order in which their leaves must appear in a committed merkle tree.
+func AssertLeavesSorted(t require.TestingT, msgs []evm_2_evm_offramp.InternalEVM2EVMMessage) {
+	for i := 1; i < len(msgs); i++ {
+		require.Less(t, msgs[i-1].SequenceNumber, msgs[i].SequenceNumber, "leaf %d is out of order", i)
+	}
+}
+
+// BuildSortedTree builds the merkle tree over msgs with their leaves in sequence number order, as the commit
+// plugin does. The sorted messages are returned along with the tree, so that leaf indices refer to them.
+func BuildSortedTree(t *testing.T, msgs []evm_2_evm_offramp.InternalEVM2EVMMessage) (*merklemulti.Tree[[32]byte], []evm_2_evm_offramp.InternalEVM2EVMMessage) {
+	sorted := make([]evm_2_evm_offramp.InternalEVM2EVMMessage, len(msgs))
+	copy(sorted, msgs)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].SequenceNumber < sorted[j].SequenceNumber
+	})
+	AssertLeavesSorted(t, sorted)
+
+	leaves := make([][32]byte, len(sorted))
+	for i, msg := range sorted {
+		// The messageId of an EVM2EVM message is its leaf hash.
+		leaves[i] = msg.MessageId
+	}
+	tree, err := merklemulti.NewTree(hashlib.NewKeccakCtx(), leaves)
+	require.NoError(t, err)
+	return tree, sorted
+}
+
+// BuildExecutionReport builds an execution report for the messages at the given indices of leafMsgs,
+// the full list of messages committed under tree.
+func BuildExecutionReport(t *testing.T, tree *merklemulti.Tree[[32]byte], leafMsgs []evm_2_evm_offramp.InternalEVM2EVMMessage, indices []int) evm_2_evm_offramp.InternalExecutionReport {
+	proof, err := tree.Prove(indices)
+	require.NoError(t, err)
+	report := evm_2_evm_offramp.InternalExecutionReport{
+		Messages:          make([]evm_2_evm_offramp.InternalEVM2EVMMessage, len(indices)),
+		OffchainTokenData: make([][][]byte, len(indices)),
+		Proofs:            proof.Hashes,
+		ProofFlagBits:     abihelpers.ProofFlagsToBits(proof.SourceFlags),
+	}
+	for i, idx := range indices {
+		report.Messages[i] = leafMsgs[idx]
+		report.OffchainTokenData[i] = make([][]byte, len(leafMsgs[idx].TokenAmounts))
+	}
+	return report
+}
+
+// executionReportLeaves returns the leaves of the messages of report, in the order its proofs prove them.
+func executionReportLeaves(report evm_2_evm_offramp.InternalExecutionReport) [][32]byte {
+	leaves := make([][32]byte, len(report.Messages))
+	for i, msg := range report.Messages {
+		leaves[i] = msg.MessageId
+	}
+	return leaves
+}
+
+// AssertExecStateForSeqNum asserts the offRamp execution state of the message with the given sequence number.
+func (c *CCIPContracts) AssertExecStateForSeqNum(t *testing.T, seqNum uint64, state abihelpers.MessageExecutionState) {
+	actual, err := c.Dest.OffRamp.GetExecutionState(nil, seqNum)
+	require.NoError(t, err)
+	require.Equal(t, state, abihelpers.MessageExecutionState(actual), "unexpected execution state for seqNum %d", seqNum)
+}
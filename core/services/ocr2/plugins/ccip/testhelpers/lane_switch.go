@@ -0,0 +1,79 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// SetLaneEnabled enables or disables the lane through the routers, which is the switch lane owners have besides
+// cursing. Disabling sets the onRamp of the dest chain to the zero address on the source router and removes the
+// offRamp from the dest router, enabling registers both ramps again. Sends to the dest chain are rejected by a
+// disabled lane, and so are executions of messages to contract receivers, which are routed through the dest router.
+func (c *CCIPContracts) SetLaneEnabled(t *testing.T, enabled bool) {
+	onRamp := common.Address{}
+	offRampRemoves := []router.RouterOffRamp{{SourceChainSelector: c.Source.ChainSelector, OffRamp: c.Dest.OffRamp.Address()}}
+	var offRampAdds []router.RouterOffRamp
+	if enabled {
+		onRamp = c.Source.OnRamp.Address()
+		offRampAdds, offRampRemoves = offRampRemoves, nil
+	}
+
+	tx, err := c.Source.Router.ApplyRampUpdates(c.Source.User, []router.RouterOnRamp{{DestChainSelector: c.Dest.ChainSelector, OnRamp: onRamp}}, nil, nil)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	tx, err = c.Dest.Router.ApplyRampUpdates(c.Dest.User, nil, offRampRemoves, offRampAdds)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+
+	supported, err := c.Source.Router.IsChainSupported(nil, c.Dest.ChainSelector)
+	require.NoError(t, err)
+	require.Equal(t, enabled, supported)
+	isOffRamp, _, err := c.Dest.Router.IsOffRamp(nil, c.Dest.OffRamp.Address())
+	require.NoError(t, err)
+	require.Equal(t, enabled, isOffRamp)
+}
+
+// SendExpectingLaneDisabled asserts that the source router rejects both the fee quote and the send of a message to
+// the dest chain with UnsupportedDestinationChain, leaving the onRamp sequence number unchanged.
+func (c *CCIPContracts) SendExpectingLaneDisabled(t *testing.T) {
+	seqNum, err := c.Source.OnRamp.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+
+	msg := gasLimitMessage(t, c.Source.User, big.NewInt(100_000))
+	_, err = c.Source.Router.GetFee(&bind.CallOpts{From: c.Source.User.From}, c.Dest.ChainSelector, msg)
+	AssertRevertedWith(t, err, router.RouterABI, "UnsupportedDestinationChain")
+	_, err = c.Source.Router.CcipSend(c.Source.User, c.Dest.ChainSelector, msg)
+	AssertRevertedWith(t, err, router.RouterABI, "UnsupportedDestinationChain")
+
+	c.Source.Chain.Commit()
+	after, err := c.Source.OnRamp.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, seqNum, after)
+}
+
+// ExecuteExpectingLaneDisabled transmits report by oracle and asserts that the offRamp rejects it with an
+// ExecutionError wrapping the OnlyOffRamp revert of the dest router, leaving every message of the report untouched.
+// The messages must be sent to contract receivers, as messages to other receivers skip the router.
+func (c *CCIPContracts) ExecuteExpectingLaneDisabled(t *testing.T, oracle SimulatedOracle, report evm_2_evm_offramp.InternalExecutionReport) {
+	_, err := c.TransmitExecutionReport(t, oracle, report)
+	AssertRevertedWith(t, err, evm_2_evm_offramp.EVM2EVMOffRampABI, "ExecutionError")
+	data := RevertData(t, err)
+	offRampABI, err := evm_2_evm_offramp.EVM2EVMOffRampMetaData.GetAbi()
+	require.NoError(t, err)
+	unpacked, err := offRampABI.Errors["ExecutionError"].Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	AssertErrorSelector(t, unpacked[0].([]byte), router.RouterABI, "OnlyOffRamp")
+
+	for _, msg := range report.Messages {
+		c.AssertExecStateForSeqNum(t, msg.SequenceNumber, abihelpers.ExecutionStateUntouched)
+	}
+}
@@ -0,0 +1,35 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestSetLaneEnabled(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	c.SendDataMessages(t, 1, big.NewInt(100_000))
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 1)
+	tree := c.CommitMessages(t, msgs)
+
+	c.SetLaneEnabled(t, false)
+	c.SendExpectingLaneDisabled(t)
+	c.ExecuteExpectingLaneDisabled(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0}))
+
+	c.SetLaneEnabled(t, true)
+	_, err := c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0}))
+	require.NoError(t, err)
+	c.AssertExecStateForSeqNum(t, msgs[0].SequenceNumber, abihelpers.ExecutionStateSuccess)
+
+	startBlock = c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	c.SendDataMessages(t, 1, big.NewInt(100_000))
+	resumed := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, resumed, 1)
+	require.Equal(t, msgs[0].SequenceNumber+1, resumed[0].SequenceNumber)
+}
@@ -0,0 +1,81 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/hashlib"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/merklemulti"
+)
+
+func TestSortedTreeProofsVerify(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+	c.SendDataMessages(t, 4, big.NewInt(200_000))
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 4)
+
+	shuffled := []evm_2_evm_offramp.InternalEVM2EVMMessage{msgs[2], msgs[0], msgs[3], msgs[1]}
+	tree, sorted := BuildSortedTree(t, shuffled)
+	AssertLeavesSorted(t, sorted)
+	require.Equal(t, msgs, sorted)
+	require.Equal(t, tree.Root(), c.CommitMessages(t, sorted).Root())
+
+	proof, err := tree.Prove([]int{1})
+	require.NoError(t, err)
+	timestamp, err := c.Dest.CommitStore.Verify(nil, [][32]byte{sorted[1].MessageId}, proof.Hashes, abihelpers.ProofFlagsToBits(proof.SourceFlags))
+	require.NoError(t, err)
+	require.NotZero(t, timestamp.Uint64())
+
+	// A tree built over the unsorted leaves has a different root, which the commitStore rejects proofs against.
+	unsortedLeaves := make([][32]byte, len(shuffled))
+	for i, msg := range shuffled {
+		unsortedLeaves[i] = msg.MessageId
+	}
+	unsortedTree, err := merklemulti.NewTree(hashlib.NewKeccakCtx(), unsortedLeaves)
+	require.NoError(t, err)
+	require.NotEqual(t, tree.Root(), unsortedTree.Root())
+	unsortedProof, err := unsortedTree.Prove([]int{3})
+	require.NoError(t, err)
+	timestamp, err = c.Dest.CommitStore.Verify(nil, [][32]byte{shuffled[3].MessageId}, unsortedProof.Hashes, abihelpers.ProofFlagsToBits(unsortedProof.SourceFlags))
+	require.NoError(t, err)
+	require.Zero(t, timestamp.Uint64())
+}
+
+// failureRecorder is a require.TestingT that records whether an assertion failed, without ending the test.
+type failureRecorder struct {
+	failed bool
+}
+
+func (r *failureRecorder) Errorf(string, ...interface{}) { r.failed = true }
+
+func (r *failureRecorder) FailNow() { r.failed = true }
+
+func TestAssertLeavesSorted(t *testing.T) {
+	testCases := []struct {
+		name    string
+		seqNums []uint64
+		fails   bool
+	}{
+		{name: "no leaves"},
+		{name: "sorted", seqNums: []uint64{1, 2, 5}},
+		{name: "out of order", seqNums: []uint64{1, 5, 2}, fails: true},
+		{name: "duplicate", seqNums: []uint64{1, 2, 2}, fails: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msgs := make([]evm_2_evm_offramp.InternalEVM2EVMMessage, len(tc.seqNums))
+			for i, seqNum := range tc.seqNums {
+				msgs[i].SequenceNumber = seqNum
+			}
+			recorder := &failureRecorder{}
+			AssertLeavesSorted(recorder, msgs)
+			require.Equal(t, tc.fails, recorder.failed)
+		})
+	}
+}
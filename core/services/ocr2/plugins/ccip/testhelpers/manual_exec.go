@@ -0,0 +1,57 @@
+package testhelpers
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+)
+
+// CommittedAt returns the time at which the root proving the messages of report was committed on the dest chain.
+func (c *CCIPContracts) CommittedAt(t *testing.T, report evm_2_evm_offramp.InternalExecutionReport) time.Time {
+	timestamp, err := c.Dest.CommitStore.Verify(nil, executionReportLeaves(report), report.Proofs, report.ProofFlagBits)
+	require.NoError(t, err)
+	require.NotZero(t, timestamp.Sign(), "root of the report is not committed")
+	return time.Unix(timestamp.Int64(), 0)
+}
+
+// ManuallyExecuteAt manually executes report as the dest user without gas limit overrides, in a block with
+// timestamp at, which must leave room for a block to be mined before it. The error returned by the offRamp
+// is passed to the caller, in which case no block is mined at at.
+func (c *CCIPContracts) ManuallyExecuteAt(t *testing.T, report evm_2_evm_offramp.InternalExecutionReport, at time.Time) (*types.Receipt, error) {
+	// Transactions are included in the block after the head, which is what the pending state sees as well.
+	SetChainClock(t, c.Dest.Chain, at.Add(-simulatedBlockInterval*time.Second))
+	gasLimitOverrides := make([]*big.Int, len(report.Messages))
+	for i := range gasLimitOverrides {
+		gasLimitOverrides[i] = big.NewInt(0)
+	}
+	tx, err := c.Dest.OffRamp.ManuallyExecute(c.Dest.User, report, gasLimitOverrides)
+	if err != nil {
+		return nil, err
+	}
+	c.Dest.Chain.Commit()
+	receipt, err := bind.WaitMined(context.Background(), c.Dest.Chain, tx)
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, "manual execution reverted")
+	require.Equal(t, uint64(at.Unix()), c.Dest.Chain.Blockchain().CurrentBlock().Time)
+	return receipt, nil
+}
+
+// AttemptManualExecTooEarly asserts that report, whose messages have not been executed yet, cannot be manually
+// executed at the last second before manual execution is enabled. The offRamp only enables it once strictly more
+// than delay, its permissionless execution threshold, has passed since the root was committed, so the attempt is
+// made exactly delay after the commit.
+func (c *CCIPContracts) AttemptManualExecTooEarly(t *testing.T, report evm_2_evm_offramp.InternalExecutionReport, delay time.Duration) {
+	config, err := c.Dest.OffRamp.GetDynamicConfig(nil)
+	require.NoError(t, err)
+	require.Equal(t, delay, time.Duration(config.PermissionLessExecutionThresholdSeconds)*time.Second, "delay is not the threshold of the offRamp")
+
+	_, err = c.ManuallyExecuteAt(t, report, c.CommittedAt(t, report).Add(delay))
+	AssertRevertedWith(t, err, evm_2_evm_offramp.EVM2EVMOffRampABI, "ManualExecutionNotYetEnabled")
+}
@@ -0,0 +1,34 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestManualExecTimeLock(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	c.SetupSimulatedOracles(t)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	c.SendDataMessages(t, 1, big.NewInt(100_000))
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 1)
+	tree := c.CommitMessages(t, msgs)
+	report := BuildExecutionReport(t, tree, msgs, []int{0})
+	delay := PermissionLessExecutionThresholdSeconds * time.Second
+	committedAt := c.CommittedAt(t, report)
+
+	_, err := c.ManuallyExecuteAt(t, report, committedAt.Add(delay-time.Second))
+	AssertRevertedWith(t, err, evm_2_evm_offramp.EVM2EVMOffRampABI, "ManualExecutionNotYetEnabled")
+	c.AttemptManualExecTooEarly(t, report, delay)
+	c.AssertExecStateForSeqNum(t, msgs[0].SequenceNumber, abihelpers.ExecutionStateUntouched)
+
+	_, err = c.ManuallyExecuteAt(t, report, committedAt.Add(delay+time.Second))
+	require.NoError(t, err)
+	c.AssertExecStateForSeqNum(t, msgs[0].SequenceNumber, abihelpers.ExecutionStateSuccess)
+}
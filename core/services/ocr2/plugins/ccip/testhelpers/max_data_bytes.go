@@ -0,0 +1,83 @@
+package testhelpers
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_onramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+// SetPerChainMaxDataBytes sets the MaxDataSize of onRamp, the onRamp of the lane to destSelector, to maxBytes. Every
+// onRamp serves a single dest chain, so the cap applies to that lane only and leaves the other lanes of the source
+// chain untouched.
+func SetPerChainMaxDataBytes(t *testing.T, chain *backends.SimulatedBackend, onRamp *evm_2_evm_onramp.EVM2EVMOnRamp, owner *bind.TransactOpts, destSelector uint64, maxBytes uint32) {
+	staticConfig, err := onRamp.GetStaticConfig(nil)
+	require.NoError(t, err)
+	require.Equal(t, destSelector, staticConfig.DestChainSelector, "onRamp does not serve the lane to %d", destSelector)
+	dynamicConfig, err := onRamp.GetDynamicConfig(nil)
+	require.NoError(t, err)
+	dynamicConfig.MaxDataSize = maxBytes
+	tx, err := onRamp.SetDynamicConfig(owner, dynamicConfig)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+
+	dynamicConfig, err = onRamp.GetDynamicConfig(nil)
+	require.NoError(t, err)
+	require.Equal(t, maxBytes, dynamicConfig.MaxDataSize)
+}
+
+// AssertMaxDataBytesPerLane asserts that a data-only message with data, sent through r paying the fee in native
+// tokens, is rejected by the lane to rejectingSelector with MessageTooLarge, while the lane to acceptingSelector
+// accepts it and emits it with its data intact. The data must exceed the cap of the rejecting lane and fit the cap
+// of the accepting one.
+func AssertMaxDataBytesPerLane(t *testing.T, chain *backends.SimulatedBackend, r *router.Router, sender *bind.TransactOpts, data []byte, rejectingSelector, acceptingSelector uint64) {
+	msg := gasLimitMessage(t, sender, big.NewInt(200_000))
+	msg.Data = data
+
+	_, err := r.GetFee(&bind.CallOpts{From: sender.From}, rejectingSelector, msg)
+	AssertRevertedWith(t, err, evm_2_evm_onramp.EVM2EVMOnRampABI, "MessageTooLarge")
+	_, err = r.CcipSend(sender, rejectingSelector, msg)
+	AssertRevertedWith(t, err, evm_2_evm_onramp.EVM2EVMOnRampABI, "MessageTooLarge")
+	revertData := RevertData(t, err)
+	onRampABI, err := evm_2_evm_onramp.EVM2EVMOnRampMetaData.GetAbi()
+	require.NoError(t, err)
+	args, err := onRampABI.Errors["MessageTooLarge"].Inputs.Unpack(revertData[4:])
+	require.NoError(t, err)
+	require.Equal(t, int64(len(data)), args[1].(*big.Int).Int64(), "size of the rejected message")
+
+	fee, err := r.GetFee(&bind.CallOpts{From: sender.From}, acceptingSelector, msg)
+	require.NoError(t, err)
+	opts := *sender
+	opts.Value = fee
+	tx, err := r.CcipSend(&opts, acceptingSelector, msg)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+
+	onRampAddress, err := r.GetOnRamp(nil, acceptingSelector)
+	require.NoError(t, err)
+	onRamp, err := evm_2_evm_onramp.NewEVM2EVMOnRamp(onRampAddress, chain)
+	require.NoError(t, err)
+	receipt, err := chain.TransactionReceipt(context.Background(), tx.Hash())
+	require.NoError(t, err)
+	var sent bool
+	for _, log := range receipt.Logs {
+		if log.Address != onRampAddress {
+			continue
+		}
+		event, err := onRamp.ParseCCIPSendRequested(*log)
+		if err != nil {
+			continue
+		}
+		require.Equal(t, hexutil.Encode(data), hexutil.Encode(event.Message.Data))
+		sent = true
+	}
+	require.True(t, sent, "lane to %d did not send the message", acceptingSelector)
+}
@@ -0,0 +1,45 @@
+package testhelpers
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/price_registry"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+func TestAssertMaxDataBytesPerLane(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+
+	// A second lane from the source chain, served by its own onRamp.
+	otherSelector := DestChainSelector + 1
+	other := c
+	other.Dest.ChainSelector = otherSelector
+	other.Source.OnRamp = nil
+	other.DeployNewOnRamp(t)
+	tx, err := c.Source.Router.ApplyRampUpdates(c.Source.User, []router.RouterOnRamp{{DestChainSelector: otherSelector, OnRamp: other.Source.OnRamp.Address()}}, nil, nil)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	tx, err = c.Source.PriceRegistry.UpdatePrices(c.Source.User, price_registry.InternalPriceUpdates{
+		TokenPriceUpdates: []price_registry.InternalTokenPriceUpdate{},
+		DestChainSelector: otherSelector,
+		UsdPerUnitGas:     big.NewInt(2000e9),
+	})
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+
+	SetPerChainMaxDataBytes(t, c.Source.Chain, c.Source.OnRamp, c.Source.User, c.Dest.ChainSelector, 100)
+	SetPerChainMaxDataBytes(t, c.Source.Chain, other.Source.OnRamp, c.Source.User, otherSelector, 1000)
+
+	data := bytes.Repeat([]byte{0xab}, 500)
+	AssertMaxDataBytesPerLane(t, c.Source.Chain, c.Source.Router, c.Source.User, data, c.Dest.ChainSelector, otherSelector)
+
+	// The caps are per lane in both directions.
+	SetPerChainMaxDataBytes(t, c.Source.Chain, c.Source.OnRamp, c.Source.User, c.Dest.ChainSelector, 1000)
+	SetPerChainMaxDataBytes(t, c.Source.Chain, other.Source.OnRamp, c.Source.User, otherSelector, 100)
+	AssertMaxDataBytesPerLane(t, c.Source.Chain, c.Source.Router, c.Source.User, data, otherSelector, c.Dest.ChainSelector)
+}
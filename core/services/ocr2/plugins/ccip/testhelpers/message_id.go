@@ -0,0 +1,33 @@
+package testhelpers
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_onramp"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/hashlib"
+)
+
+// MessageMetadataHash returns the metadata hash of the lane, which the onRamp mixes into the ID of every message.
+func (c *CCIPContracts) MessageMetadataHash() [32]byte {
+	return hashlib.GetMessageMetaDataHash(hashlib.NewKeccakCtx(), c.Source.ChainSelector, c.Source.OnRamp.Address(), c.Dest.ChainSelector)
+}
+
+// ComputeMessageID computes the message ID the onRamp derives for msg on the lane with the given metadata hash.
+// The MessageId field of msg is not part of the derivation and is ignored.
+func ComputeMessageID(t *testing.T, msg evm_2_evm_offramp.InternalEVM2EVMMessage, metadataHash [32]byte) [32]byte {
+	id, err := hashlib.HashMessage(hashlib.NewKeccakCtx(), metadataHash, &msg)
+	require.NoError(t, err)
+	return id
+}
+
+// MessageIDFromLog returns the ID of the message sent in log, which must be a CCIPSendRequested log of onRamp.
+func MessageIDFromLog(t *testing.T, onRamp *evm_2_evm_onramp.EVM2EVMOnRamp, log types.Log) [32]byte {
+	require.Equal(t, onRamp.Address(), log.Address, "log was not emitted by the onRamp")
+	sent, err := onRamp.ParseCCIPSendRequested(log)
+	require.NoError(t, err)
+	return sent.Message.MessageId
+}
@@ -0,0 +1,71 @@
+package testhelpers
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestComputeMessageID(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	sender := c.Source.User.From
+	receiver := c.Dest.Receivers[0].Receiver.Address()
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, receiver),
+		Data:         []byte("hello"),
+		TokenAmounts: []router.ClientEVMTokenAmount{{Token: c.Source.LinkToken.Address(), Amount: Link(1)}},
+		FeeToken:     c.Source.LinkToken.Address(),
+		ExtraArgs:    extraArgs,
+	}
+
+	// Everything the ID is derived from is known before sending.
+	fee, err := c.Source.Router.GetFee(nil, c.Dest.ChainSelector, msg)
+	require.NoError(t, err)
+	nextSeqNum, err := c.Source.OnRamp.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	nonce, err := c.Source.OnRamp.GetSenderNonce(nil, sender)
+	require.NoError(t, err)
+	expected := evm_2_evm_offramp.InternalEVM2EVMMessage{
+		SourceChainSelector: c.Source.ChainSelector,
+		Sender:              sender,
+		Receiver:            receiver,
+		SequenceNumber:      nextSeqNum,
+		GasLimit:            big.NewInt(200_000),
+		Strict:              false,
+		Nonce:               nonce + 1,
+		FeeToken:            c.Source.LinkToken.Address(),
+		FeeTokenAmount:      fee,
+		Data:                []byte("hello"),
+		TokenAmounts:        []evm_2_evm_offramp.ClientEVMTokenAmount{{Token: c.Source.LinkToken.Address(), Amount: Link(1)}},
+		SourceTokenData:     [][]byte{{}},
+	}
+	expectedID := ComputeMessageID(t, expected, c.MessageMetadataHash())
+
+	tx, err := c.Source.LinkToken.Approve(c.Source.User, c.Source.Router.Address(), new(big.Int).Add(fee, Link(1)))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	tx = c.SendRequest(t, msg)
+	receipt, err := c.Source.Chain.TransactionReceipt(context.Background(), tx.Hash())
+	require.NoError(t, err)
+	var ids [][32]byte
+	for _, log := range receipt.Logs {
+		if log.Address != c.Source.OnRamp.Address() || log.Topics[0] != abihelpers.EventSignatures.SendRequested {
+			continue
+		}
+		ids = append(ids, MessageIDFromLog(t, c.Source.OnRamp, *log))
+	}
+	require.Equal(t, [][32]byte{expectedID}, ids)
+
+	// Any change to the message changes its ID.
+	expected.Nonce++
+	require.NotEqual(t, expectedID, ComputeMessageID(t, expected, c.MessageMetadataHash()))
+}
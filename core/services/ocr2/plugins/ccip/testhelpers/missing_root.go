@@ -0,0 +1,25 @@
+package testhelpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// ExecuteAgainstMissingRootExpectingReject asserts that transmitting report through oracle reverts with
+// RootNotCommitted, because the root its proofs lead to was never committed, and that none of its messages
+// change state.
+func (c *CCIPContracts) ExecuteAgainstMissingRootExpectingReject(t *testing.T, oracle SimulatedOracle, report evm_2_evm_offramp.InternalExecutionReport) {
+	timestamp, err := c.Dest.CommitStore.Verify(nil, executionReportLeaves(report), report.Proofs, report.ProofFlagBits)
+	require.NoError(t, err)
+	require.Zero(t, timestamp.Sign(), "root of the report is committed")
+
+	_, err = c.TransmitExecutionReport(t, oracle, report)
+	AssertRevertedWith(t, err, evm_2_evm_offramp.EVM2EVMOffRampABI, "RootNotCommitted")
+	for _, msg := range report.Messages {
+		c.AssertExecStateForSeqNum(t, msg.SequenceNumber, abihelpers.ExecutionStateUntouched)
+	}
+}
@@ -0,0 +1,30 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestExecuteAgainstMissingRoot(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	c.SendDataMessages(t, 2, big.NewInt(100_000))
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 2)
+
+	tree, leafMsgs := BuildSortedTree(t, msgs)
+	report := BuildExecutionReport(t, tree, leafMsgs, []int{0, 1})
+	c.ExecuteAgainstMissingRootExpectingReject(t, oracles[0], report)
+
+	c.CommitMessages(t, msgs)
+	_, err := c.TransmitExecutionReport(t, oracles[0], report)
+	require.NoError(t, err)
+	for _, msg := range msgs {
+		c.AssertExecStateForSeqNum(t, msg.SequenceNumber, abihelpers.ExecutionStateSuccess)
+	}
+}
@@ -0,0 +1,37 @@
+package testhelpers
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_onramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+// toOnRampMessage converts msg as sent to the router into the message the router forwards to the onRamp.
+func toOnRampMessage(msg router.ClientEVM2AnyMessage) evm_2_evm_onramp.ClientEVM2AnyMessage {
+	tokenAmounts := make([]evm_2_evm_onramp.ClientEVMTokenAmount, len(msg.TokenAmounts))
+	for i, tokenAmount := range msg.TokenAmounts {
+		tokenAmounts[i] = evm_2_evm_onramp.ClientEVMTokenAmount{Token: tokenAmount.Token, Amount: tokenAmount.Amount}
+	}
+	return evm_2_evm_onramp.ClientEVM2AnyMessage{
+		Receiver:     msg.Receiver,
+		Data:         msg.Data,
+		TokenAmounts: tokenAmounts,
+		FeeToken:     msg.FeeToken,
+		ExtraArgs:    msg.ExtraArgs,
+	}
+}
+
+// CallOnRampDirectlyExpectingReject asserts that attacker cannot bypass the router by forwarding msg to onRamp
+// itself. Only the router configured on the onRamp may forward messages, since the onRamp trusts it to have
+// collected the fee, so the call is rejected with MustBeCalledByRouter even when it claims the quoted fee.
+func CallOnRampDirectlyExpectingReject(t *testing.T, onRamp *evm_2_evm_onramp.EVM2EVMOnRamp, attacker *bind.TransactOpts, msg router.ClientEVM2AnyMessage) {
+	onRampMsg := toOnRampMessage(msg)
+	fee, err := onRamp.GetFee(nil, onRampMsg)
+	require.NoError(t, err)
+	_, err = onRamp.ForwardFromRouter(attacker, onRampMsg, fee, attacker.From)
+	AssertRevertedWith(t, err, evm_2_evm_onramp.EVM2EVMOnRampABI, "MustBeCalledByRouter")
+}
@@ -0,0 +1,44 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+func TestOnRampRouterOnlyAccess(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, c.Dest.Receivers[0].Receiver.Address()),
+		Data:         []byte("hello"),
+		TokenAmounts: []router.ClientEVMTokenAmount{},
+		FeeToken:     c.Source.LinkToken.Address(),
+		ExtraArgs:    extraArgs,
+	}
+	tx, err := c.Source.LinkToken.Approve(c.Source.User, c.Source.Router.Address(), HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	feesBefore, err := c.Source.OnRamp.GetNopFeesJuels(nil)
+	require.NoError(t, err)
+
+	// Sending through the router is accepted and charges the fee.
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+	c.SendRequest(t, msg)
+	require.Len(t, c.SendRequestedMessages(t, startBlock), 1)
+	feesAfter, err := c.Source.OnRamp.GetNopFeesJuels(nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, feesAfter.Cmp(feesBefore))
+
+	// Forwarding directly to the onRamp is rejected, for the onRamp owner as much as for anyone else.
+	CallOnRampDirectlyExpectingReject(t, c.Source.OnRamp, c.Source.User, msg)
+	CallOnRampDirectlyExpectingReject(t, c.Source.OnRamp, NewFundedUser(t, c.Source.Chain, c.Source.User), msg)
+	feesAfterReject, err := c.Source.OnRamp.GetNopFeesJuels(nil)
+	require.NoError(t, err)
+	require.Equal(t, feesAfter.String(), feesAfterReject.String())
+}
@@ -0,0 +1,54 @@
+package testhelpers
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/libocr/offchainreporting2/confighelper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/commit_store"
+)
+
+// RotateOracleSet replaces the commit and exec DONs of the lane with newOracles, setting the OCR2 configs of the
+// commitStore and the offRamp again with the default onchain and offchain configs. Roots committed and messages
+// sent before the rotation are left untouched, so that the new DON picks them up where the previous one stopped.
+func (c *CCIPContracts) RotateOracleSet(t *testing.T, newOracles []SimulatedOracle) {
+	c.Oracles = make([]confighelper.OracleIdentityExtra, len(newOracles))
+	for i, oracle := range newOracles {
+		c.Oracles[i] = oracle.Identity(t)
+	}
+	c.SetupOnchainConfig(t,
+		c.CreateDefaultCommitOnchainConfig(t),
+		c.CreateDefaultCommitOffchainConfig(t),
+		c.CreateDefaultExecOnchainConfig(t),
+		c.CreateDefaultExecOffchainConfig(t),
+	)
+}
+
+// AssertNewConfigActive asserts that newOracles, rotated in by RotateOracleSet, are the only transmitters of the
+// commitStore and the offRamp, and that the commitStore rejects report, a commit of the next messages, both when
+// transmitted by a transmitter of oldOracles and when signed by oldOracles. Neither attempt commits the report.
+func (c *CCIPContracts) AssertNewConfigActive(t *testing.T, oldOracles, newOracles []SimulatedOracle, report []byte) {
+	expected := make([]common.Address, len(newOracles))
+	for i, oracle := range newOracles {
+		expected[i] = oracle.Transmitter.From
+	}
+	commitTransmitters, err := c.Dest.CommitStore.GetTransmitters(nil)
+	require.NoError(t, err)
+	require.Equal(t, expected, commitTransmitters)
+	execTransmitters, err := c.Dest.OffRamp.GetTransmitters(nil)
+	require.NoError(t, err)
+	require.Equal(t, expected, execTransmitters)
+
+	c.PostCommitFromUnauthorizedExpectingReject(t, newOracles, oldOracles[0].Transmitter, report)
+
+	nextSeqNum, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	_, err = c.TransmitCommitReport(t, oldOracles, newOracles[0].Transmitter, report)
+	AssertRevertedWith(t, err, commit_store.CommitStoreABI, "UnauthorizedSigner")
+	c.Dest.Chain.Commit()
+	after, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, nextSeqNum, after)
+}
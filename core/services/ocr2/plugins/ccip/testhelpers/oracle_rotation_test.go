@@ -0,0 +1,63 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestRotateOracleSet(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oldOracles := c.SetupSimulatedOracles(t)
+	commitDigest, err := c.Dest.CommitStore.LatestConfigDetails(nil)
+	require.NoError(t, err)
+	execDigest, err := c.Dest.OffRamp.LatestConfigDetails(nil)
+	require.NoError(t, err)
+
+	// The previous DON commits the first messages, which are in flight for execution during the rotation.
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	c.SendDataMessages(t, 2, big.NewInt(100_000))
+	before := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, before, 2)
+	report, beforeTree := encodeMessagesCommitReport(t, before)
+	tx, err := c.TransmitCommitReport(t, oldOracles, oldOracles[0].Transmitter, report)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+
+	newOracles := NewSimulatedOracles(t, c.Dest.Chain, c.Dest.User, 4)
+	c.RotateOracleSet(t, newOracles)
+	newCommitDigest, err := c.Dest.CommitStore.LatestConfigDetails(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, commitDigest.ConfigDigest, newCommitDigest.ConfigDigest)
+	newExecDigest, err := c.Dest.OffRamp.LatestConfigDetails(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, execDigest.ConfigDigest, newExecDigest.ConfigDigest)
+
+	startBlock = c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	c.SendDataMessages(t, 2, big.NewInt(100_000))
+	after := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, after, 2)
+	require.Equal(t, before[1].SequenceNumber+1, after[0].SequenceNumber)
+	report, afterTree := encodeMessagesCommitReport(t, after)
+	c.AssertNewConfigActive(t, oldOracles, newOracles, report)
+
+	// The new DON commits the next messages right after the last root of the previous one, and executes both.
+	tx, err = c.TransmitCommitReport(t, newOracles, newOracles[1].Transmitter, report)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	next, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, after[1].SequenceNumber+1, next)
+
+	_, err = c.TransmitExecutionReport(t, newOracles[0], BuildExecutionReport(t, beforeTree, before, []int{0, 1}))
+	require.NoError(t, err)
+	_, err = c.TransmitExecutionReport(t, newOracles[0], BuildExecutionReport(t, afterTree, after, []int{0, 1}))
+	require.NoError(t, err)
+	for _, msg := range append(before, after...) {
+		c.AssertExecStateForSeqNum(t, msg.SequenceNumber, abihelpers.ExecutionStateSuccess)
+	}
+}
@@ -0,0 +1,63 @@
+package testhelpers
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_onramp"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// AssertOrderingPreservedAcrossReorg asserts that the messages with seqNums, sent on the canonical source chain
+// after a reorg of it, were delivered in order. Each of them must have been sent exactly once on the canonical
+// source chain, with the nonces of every sender rising with the sequence numbers of its messages, and executed
+// successfully exactly once on the dest chain, in ascending sequence number order.
+func (c *CCIPContracts) AssertOrderingPreservedAcrossReorg(t *testing.T, seqNums []uint64) {
+	require.NotEmpty(t, seqNums)
+	wanted := make(map[uint64]bool, len(seqNums))
+	for _, seqNum := range seqNums {
+		wanted[seqNum] = true
+	}
+	sorted := append([]uint64{}, seqNums...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	sent := make(map[uint64]evm_2_evm_onramp.InternalEVM2EVMMessage, len(seqNums))
+	sendIt, err := c.Source.OnRamp.FilterCCIPSendRequested(&bind.FilterOpts{})
+	require.NoError(t, err)
+	defer sendIt.Close()
+	for sendIt.Next() {
+		msg := sendIt.Event.Message
+		if !wanted[msg.SequenceNumber] {
+			continue
+		}
+		_, duplicate := sent[msg.SequenceNumber]
+		require.False(t, duplicate, "seqNum %d was sent more than once on the canonical source chain", msg.SequenceNumber)
+		sent[msg.SequenceNumber] = msg
+	}
+	require.NoError(t, sendIt.Error())
+	lastNonce := make(map[common.Address]uint64)
+	for _, seqNum := range sorted {
+		msg, ok := sent[seqNum]
+		require.True(t, ok, "seqNum %d was not sent on the canonical source chain", seqNum)
+		require.Greater(t, msg.Nonce, lastNonce[msg.Sender], "nonce of seqNum %d does not follow the earlier messages of its sender", seqNum)
+		lastNonce[msg.Sender] = msg.Nonce
+	}
+
+	var executed []uint64
+	execIt, err := c.Dest.OffRamp.FilterExecutionStateChanged(&bind.FilterOpts{}, nil, nil)
+	require.NoError(t, err)
+	defer execIt.Close()
+	for execIt.Next() {
+		if !wanted[execIt.Event.SequenceNumber] {
+			continue
+		}
+		require.Equal(t, abihelpers.ExecutionStateSuccess, abihelpers.MessageExecutionState(execIt.Event.State), "seqNum %d failed", execIt.Event.SequenceNumber)
+		executed = append(executed, execIt.Event.SequenceNumber)
+	}
+	require.NoError(t, execIt.Error())
+	require.Equal(t, sorted, executed, "messages were not executed exactly once in sequence number order")
+}
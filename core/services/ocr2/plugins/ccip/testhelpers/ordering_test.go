@@ -0,0 +1,77 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestOrderingPreservedAcrossReorg(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	tx, err := c.Source.LinkToken.Approve(c.Source.User, c.Source.Router.Address(), HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(100_000), false)
+	require.NoError(t, err)
+	send := func(data string) *types.Transaction {
+		tx, err := c.Source.Router.CcipSend(c.Source.User, c.Dest.ChainSelector, router.ClientEVM2AnyMessage{
+			Receiver:     MustEncodeAddress(t, c.Dest.Receivers[0].Receiver.Address()),
+			Data:         []byte(data),
+			TokenAmounts: []router.ClientEVMTokenAmount{},
+			FeeToken:     c.Source.LinkToken.Address(),
+			ExtraArgs:    extraArgs,
+		})
+		require.NoError(t, err)
+		return tx
+	}
+
+	// Three messages in blocks of their own, which the reorg drops.
+	ancestor := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+	for _, data := range []string{"a", "b", "c"} {
+		ConfirmTxs(t, []*types.Transaction{send(data)}, c.Source.Chain)
+	}
+	orphaned := c.SendRequestedMessages(t, ancestor+1)
+	require.Len(t, orphaned, 3)
+	Reorg(t, c.Source.Chain, ancestor)
+	require.Empty(t, c.SendRequestedMessages(t, ancestor+1))
+
+	// The messages are sent again in reverse, the first two in a single block, and take over the sequence numbers
+	// and nonces of the orphaned ones in their new order.
+	ConfirmTxs(t, []*types.Transaction{send("c"), send("b")}, c.Source.Chain)
+	ConfirmTxs(t, []*types.Transaction{send("a")}, c.Source.Chain)
+	msgs := c.SendRequestedMessages(t, ancestor+1)
+	require.Len(t, msgs, 3)
+	seqNums := make([]uint64, len(msgs))
+	for i, msg := range msgs {
+		require.Equal(t, orphaned[i].SequenceNumber, msg.SequenceNumber)
+		require.Equal(t, orphaned[i].Nonce, msg.Nonce)
+		require.Equal(t, orphaned[len(orphaned)-1-i].Data, msg.Data)
+		seqNums[i] = msg.SequenceNumber
+	}
+
+	// A message is not executed ahead of the earlier messages of its sender.
+	tree := c.CommitMessages(t, msgs)
+	receipt, err := c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{1}))
+	require.NoError(t, err)
+	var skipped bool
+	for _, log := range receipt.Logs {
+		if log.Address != c.Dest.OffRamp.Address() {
+			continue
+		}
+		if _, err := c.Dest.OffRamp.ParseSkippedIncorrectNonce(*log); err == nil {
+			skipped = true
+		}
+	}
+	require.True(t, skipped, "out of order message was not skipped")
+	c.AssertExecStateForSeqNum(t, msgs[1].SequenceNumber, abihelpers.ExecutionStateUntouched)
+
+	_, err = c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0, 1, 2}))
+	require.NoError(t, err)
+	c.AssertOrderingPreservedAcrossReorg(t, seqNums)
+}
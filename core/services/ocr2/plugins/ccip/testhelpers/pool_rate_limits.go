@@ -0,0 +1,72 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/lock_release_token_pool"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/generated/link_token_interface"
+)
+
+// AssertInboundOutboundLimitsIndependent asserts that draining the outbound rate limit of pool, the one lockOrBurn
+// consumes, leaves its inbound rate limit, the one releaseOrMint consumes, at full capacity, and vice versa. Pools
+// keep a bucket per ramp, so owner allows itself as both an onRamp and an offRamp of pool with identical limits and
+// drains either bucket by calling the pool directly, while the buckets of the lane's ramps are left untouched. The
+// releases send owner back the tokens it funded pool with, and the owner ramps are revoked again afterwards.
+func AssertInboundOutboundLimitsIndependent(t *testing.T, chain *backends.SimulatedBackend, pool *lock_release_token_pool.LockReleaseTokenPool, owner *bind.TransactOpts) {
+	capacity := Link(1)
+	tokenAddress, err := pool.GetToken(nil)
+	require.NoError(t, err)
+	token, err := link_token_interface.NewLinkToken(tokenAddress, chain)
+	require.NoError(t, err)
+	// One release per direction.
+	tx, err := token.Transfer(owner, pool.Address(), new(big.Int).Mul(capacity, big.NewInt(2)))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+
+	ownerRamps := []lock_release_token_pool.TokenPoolRampUpdate{{Ramp: owner.From, Allowed: true,
+		// A rate of 1 refills a negligible fraction of the capacity in the blocks of the assertion.
+		RateLimiterConfig: lock_release_token_pool.RateLimiterConfig{IsEnabled: true, Capacity: capacity, Rate: big.NewInt(1)}}}
+	lockOrBurn := func() (*types.Transaction, error) {
+		return pool.LockOrBurn(owner, owner.From, nil, capacity, 0, nil)
+	}
+	releaseOrMint := func() (*types.Transaction, error) {
+		return pool.ReleaseOrMint(owner, nil, owner.From, capacity, 0, nil)
+	}
+
+	for _, directions := range []struct {
+		drain, other func() (*types.Transaction, error)
+		otherBucket  func(*bind.CallOpts, common.Address) (lock_release_token_pool.RateLimiterTokenBucket, error)
+	}{
+		{drain: lockOrBurn, other: releaseOrMint, otherBucket: pool.CurrentOffRampRateLimiterState},
+		{drain: releaseOrMint, other: lockOrBurn, otherBucket: pool.CurrentOnRampRateLimiterState},
+	} {
+		// Adding the ramps starts both of their buckets out full.
+		tx, err = pool.ApplyRampUpdates(owner, ownerRamps, ownerRamps)
+		require.NoError(t, err)
+		ConfirmTxs(t, []*types.Transaction{tx}, chain)
+
+		tx, err = directions.drain()
+		require.NoError(t, err)
+		ConfirmTxs(t, []*types.Transaction{tx}, chain)
+		_, err = directions.drain()
+		AssertRevertedWith(t, err, lock_release_token_pool.LockReleaseTokenPoolABI, "TokenRateLimitReached")
+
+		bucket, err := directions.otherBucket(nil, owner.From)
+		require.NoError(t, err)
+		require.Equal(t, capacity.String(), bucket.Tokens.String(), "draining one bucket consumed the other")
+		tx, err = directions.other()
+		require.NoError(t, err, "the other bucket rejected a transfer within its capacity")
+		ConfirmTxs(t, []*types.Transaction{tx}, chain)
+
+		tx, err = pool.ApplyRampUpdates(owner, revokedRamps(ownerRamps), revokedRamps(ownerRamps))
+		require.NoError(t, err)
+		ConfirmTxs(t, []*types.Transaction{tx}, chain)
+	}
+}
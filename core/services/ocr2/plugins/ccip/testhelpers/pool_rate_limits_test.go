@@ -0,0 +1,48 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/lock_release_token_pool"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestAssertInboundOutboundLimitsIndependent(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	AssertInboundOutboundLimitsIndependent(t, c.Source.Chain, c.Source.Pool, c.Source.User)
+	AssertInboundOutboundLimitsIndependent(t, c.Dest.Chain, c.Dest.Pool, c.Dest.User)
+}
+
+func TestInboundTransferWithOutboundLimitReached(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	receiver := c.Dest.Receivers[0].Receiver.Address()
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	_, err := c.SendTokenFrom(t, c.Source.User, Link(1))
+	require.NoError(t, err)
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 1)
+	tree := c.CommitMessages(t, msgs)
+
+	// Max out the outbound limit of the dest pool, which the lane only sends into, through an onRamp of its own.
+	tx, err := c.Dest.Pool.ApplyRampUpdates(c.Dest.User, []lock_release_token_pool.TokenPoolRampUpdate{{Ramp: c.Dest.User.From, Allowed: true,
+		RateLimiterConfig: lock_release_token_pool.RateLimiterConfig{IsEnabled: true, Capacity: Link(5), Rate: big.NewInt(1)}}}, nil)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	tx, err = c.Dest.Pool.LockOrBurn(c.Dest.User, c.Dest.User.From, nil, Link(5), 0, nil)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	_, err = c.Dest.Pool.LockOrBurn(c.Dest.User, c.Dest.User.From, nil, Link(5), 0, nil)
+	AssertRevertedWith(t, err, lock_release_token_pool.LockReleaseTokenPoolABI, "TokenRateLimitReached")
+
+	// The inbound transfer is within the inbound limit of the offRamp, and is released.
+	balanceBefore := GetBalance(t, c.Dest.Chain, c.Dest.LinkToken.Address(), receiver)
+	_, err = c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0}))
+	require.NoError(t, err)
+	c.AssertExecStateForSeqNum(t, msgs[0].SequenceNumber, abihelpers.ExecutionStateSuccess)
+	require.Equal(t, new(big.Int).Add(balanceBefore, Link(1)).String(), GetBalance(t, c.Dest.Chain, c.Dest.LinkToken.Address(), receiver).String())
+}
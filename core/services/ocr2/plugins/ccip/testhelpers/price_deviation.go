@@ -0,0 +1,59 @@
+package testhelpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/smartcontractkit/libocr/offchainreporting2/confighelper"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting2/types"
+	"github.com/stretchr/testify/require"
+
+	ccipconfig "github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/config"
+)
+
+// SetPriceDeviationThreshold reconfigures the commit DON of the lane, which must have its oracles set up, to
+// include token and gas price updates within the fee update heartbeat only if they deviate by more than bps
+// basis points from the latest price on chain. The rest of the configs are the defaults.
+func (c *CCIPContracts) SetPriceDeviationThreshold(t *testing.T, bps uint16) {
+	require.NotEmpty(t, c.Oracles, "lane has no oracles")
+	// A basis point is 1e5 parts per billion.
+	deviationPPB := uint32(bps) * 1e5
+	c.SetupCommitOCR2Config(t, c.CreateDefaultCommitOnchainConfig(t), c.createCommitOffchainConfig(t, 10*time.Second, 5*time.Second, deviationPPB))
+	require.Equal(t, deviationPPB, c.CommitOffchainConfig(t).FeeUpdateDeviationPPB)
+}
+
+// CommitOffchainConfig returns the offchain config of the commit plugin as last set on the commitStore, decoded
+// from the OCR config the oracles read it from.
+func (c *CCIPContracts) CommitOffchainConfig(t *testing.T) ccipconfig.CommitOffchainConfig {
+	it, err := c.Dest.CommitStore.FilterConfigSet0(&bind.FilterOpts{})
+	require.NoError(t, err)
+	defer it.Close()
+	var configSet *ocrtypes.ContractConfig
+	for it.Next() {
+		configSet = &ocrtypes.ContractConfig{
+			ConfigDigest:          it.Event.ConfigDigest,
+			ConfigCount:           it.Event.ConfigCount,
+			Signers:               make([]ocrtypes.OnchainPublicKey, len(it.Event.Signers)),
+			Transmitters:          make([]ocrtypes.Account, len(it.Event.Transmitters)),
+			F:                     it.Event.F,
+			OnchainConfig:         it.Event.OnchainConfig,
+			OffchainConfigVersion: it.Event.OffchainConfigVersion,
+			OffchainConfig:        it.Event.OffchainConfig,
+		}
+		for i, signer := range it.Event.Signers {
+			configSet.Signers[i] = signer.Bytes()
+		}
+		for i, transmitter := range it.Event.Transmitters {
+			configSet.Transmitters[i] = ocrtypes.Account(transmitter.Hex())
+		}
+	}
+	require.NoError(t, it.Error())
+	require.NotNil(t, configSet, "commitStore has no OCR config")
+
+	publicConfig, err := confighelper.PublicConfigFromContractConfig(false, *configSet)
+	require.NoError(t, err)
+	offchainConfig, err := ccipconfig.DecodeOffchainConfig[ccipconfig.CommitOffchainConfig](publicConfig.ReportingPluginConfig)
+	require.NoError(t, err)
+	return offchainConfig
+}
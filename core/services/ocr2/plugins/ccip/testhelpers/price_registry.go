@@ -0,0 +1,49 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/price_registry"
+)
+
+// tokenPriceUpdate returns price updates that only set the USD price of token.
+func tokenPriceUpdate(token common.Address, price *big.Int) price_registry.InternalPriceUpdates {
+	return price_registry.InternalPriceUpdates{
+		TokenPriceUpdates: []price_registry.InternalTokenPriceUpdate{{SourceToken: token, UsdPerToken: price}},
+		UsdPerUnitGas:     big.NewInt(0),
+	}
+}
+
+// UpdatePriceExpectingUnauthorized asserts that attacker, being neither the owner nor a price updater of registry,
+// cannot write the price of token.
+func UpdatePriceExpectingUnauthorized(t *testing.T, registry *price_registry.PriceRegistry, attacker *bind.TransactOpts, token common.Address, price *big.Int) {
+	_, err := registry.UpdatePrices(attacker, tokenPriceUpdate(token, price))
+	AssertRevertedWith(t, err, price_registry.PriceRegistryABI, "OnlyCallableByUpdaterOrOwner")
+}
+
+// ConfigureTokenDecimals makes token, which has the given decimals, a fee token of registry priced at price, the USD
+// value with 18 decimals of one whole token, as owner. The registry prices 1e18 of the smallest denomination of a
+// token, so price is scaled by the decimals before it is set: up for fewer than 18 decimals and down for more. The
+// scaled price must neither lose all its precision nor exceed the uint224 the registry stores prices in.
+func ConfigureTokenDecimals(t *testing.T, chain *backends.SimulatedBackend, registry *price_registry.PriceRegistry, owner *bind.TransactOpts, token common.Address, decimals uint8, price *big.Int) {
+	registryPrice := registryTokenPrice(price, decimals)
+	require.Positive(t, registryPrice.Sign(), "price of token with %d decimals rounds down to zero", decimals)
+	require.LessOrEqual(t, registryPrice.BitLen(), 224, "price of token with %d decimals overflows", decimals)
+
+	tx1, err := registry.ApplyFeeTokensUpdates(owner, []common.Address{token}, nil)
+	require.NoError(t, err)
+	tx2, err := registry.UpdatePrices(owner, tokenPriceUpdate(token, registryPrice))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx1, tx2}, chain)
+
+	onChainPrice, err := registry.GetTokenPrice(nil, token)
+	require.NoError(t, err)
+	require.Equal(t, registryPrice.String(), onChainPrice.Value.String())
+}
@@ -0,0 +1,37 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceRegistryUpdaterAccessControl(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	registry := c.Dest.PriceRegistry
+	updater := NewFundedUser(t, c.Dest.Chain, c.Dest.User)
+	attacker := NewFundedUser(t, c.Dest.Chain, c.Dest.User)
+	token := c.Dest.LinkToken.Address()
+
+	tx, err := registry.ApplyPriceUpdatersUpdates(c.Dest.User, []common.Address{updater.From}, []common.Address{})
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+
+	tx, err = registry.UpdatePrices(updater, tokenPriceUpdate(token, big.NewInt(9e18)))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+
+	UpdatePriceExpectingUnauthorized(t, registry, attacker, token, big.NewInt(1))
+	price, err := registry.GetTokenPrice(nil, token)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(9e18).String(), price.Value.String())
+
+	// Removed updaters lose access.
+	tx, err = registry.ApplyPriceUpdatersUpdates(c.Dest.User, []common.Address{}, []common.Address{updater.From})
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	UpdatePriceExpectingUnauthorized(t, registry, updater, token, big.NewInt(1))
+}
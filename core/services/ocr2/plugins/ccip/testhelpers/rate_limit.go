@@ -0,0 +1,21 @@
+package testhelpers
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_onramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+// SendMultiTokenExpectingAggregateLimit asserts that sending msg, which transfers several tokens, from sender
+// through r is rejected because the combined value of its tokens exceeds the aggregate rate limit capacity of
+// the onRamp. The router must be approved to transfer the tokens and fee of msg, so that the transfers to the
+// pools do not fail first.
+func SendMultiTokenExpectingAggregateLimit(t *testing.T, r *router.Router, sender *bind.TransactOpts, destSelector uint64, msg router.ClientEVM2AnyMessage) {
+	require.Greater(t, len(msg.TokenAmounts), 1, "message does not transfer multiple tokens")
+	_, err := r.CcipSend(sender, destSelector, msg)
+	AssertRevertedWith(t, err, evm_2_evm_onramp.EVM2EVMOnRampABI, "AggregateValueMaxCapacityExceeded")
+}
@@ -0,0 +1,57 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+func TestAggregateRateLimitAcrossTokens(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	linkAmount := new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18)) // $80
+	wethAmount := new(big.Int).Mul(big.NewInt(15), big.NewInt(1e18)) // $30
+
+	c.Source.User.Value = wethAmount
+	tx, err := c.Source.WrappedNative.Deposit(c.Source.User)
+	c.Source.User.Value = nil
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	approveLink, err := c.Source.LinkToken.Approve(c.Source.User, c.Source.Router.Address(), HundredLink)
+	require.NoError(t, err)
+	approveWeth, err := c.Source.WrappedNative.Approve(c.Source.User, c.Source.Router.Address(), wethAmount)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{approveLink, approveWeth}, c.Source.Chain)
+
+	// Each token is within the limit of its pool, but together they exceed the $100 aggregate capacity.
+	linkBucket, err := c.Source.Pool.CurrentOnRampRateLimiterState(nil, c.Source.OnRamp.Address())
+	require.NoError(t, err)
+	require.Positive(t, linkBucket.Tokens.Cmp(linkAmount))
+	wethBucket, err := c.Source.WrappedNativePool.CurrentOnRampRateLimiterState(nil, c.Source.OnRamp.Address())
+	require.NoError(t, err)
+	require.Positive(t, wethBucket.Tokens.Cmp(wethAmount))
+	aggregateBucket, err := c.Source.OnRamp.CurrentRateLimiterState(nil)
+	require.NoError(t, err)
+	require.Equal(t, LinkUSDValue(100).String(), aggregateBucket.Capacity.String())
+
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver: MustEncodeAddress(t, c.Dest.Receivers[0].Receiver.Address()),
+		Data:     []byte{},
+		TokenAmounts: []router.ClientEVMTokenAmount{
+			{Token: c.Source.LinkToken.Address(), Amount: linkAmount},
+			{Token: c.Source.WrappedNative.Address(), Amount: wethAmount},
+		},
+		FeeToken:  c.Source.LinkToken.Address(),
+		ExtraArgs: extraArgs,
+	}
+	SendMultiTokenExpectingAggregateLimit(t, c.Source.Router, c.Source.User, c.Dest.ChainSelector, msg)
+
+	// Without the second token the message is within the aggregate capacity.
+	msg.TokenAmounts = msg.TokenAmounts[:1]
+	c.SendRequest(t, msg)
+}
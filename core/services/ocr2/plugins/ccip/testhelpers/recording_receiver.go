@@ -0,0 +1,97 @@
+package testhelpers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/maybe_revert_message_receiver"
+)
+
+// recordingReceiverRuntime is the assembly of a receiver whose ccipReceive records every message it receives by
+// logging its ABI encoded arguments, its calldata without the selector, as the data of an anonymous log.
+const recordingReceiverRuntime = `
+PUSH 0
+CALLDATALOAD
+PUSH 224
+SHR
+DUP1
+PUSH 0x01ffc9a7
+EQ
+JUMPI @supportsInterface
+PUSH %[1]s
+EQ
+JUMPI @ccipReceive
+PUSH 0
+DUP1
+REVERT
+
+ccipReceive:
+PUSH 4
+CALLDATASIZE
+SUB
+DUP1
+PUSH 4
+PUSH 0
+CALLDATACOPY
+PUSH 0
+LOG0
+STOP
+` + receiverSupportsInterface
+
+// DeployRecordingReceiver deploys a message receiver that accepts every message and records it as received, so
+// that tests can inspect exactly what the router delivered.
+func DeployRecordingReceiver(t *testing.T, chain *backends.SimulatedBackend, owner *bind.TransactOpts) common.Address {
+	return deployReceiver(t, chain, owner, assemble(t, fmt.Sprintf(recordingReceiverRuntime, ccipReceiveSelector(t))))
+}
+
+// ReceivedMessages returns the messages recorded by receiver, as deployed by DeployRecordingReceiver, in the order
+// it received them.
+func ReceivedMessages(t *testing.T, chain *backends.SimulatedBackend, receiver common.Address) []maybe_revert_message_receiver.ClientAny2EVMMessage {
+	receiverABI, err := maybe_revert_message_receiver.MaybeRevertMessageReceiverMetaData.GetAbi()
+	require.NoError(t, err)
+	logs, err := chain.FilterLogs(context.Background(), ethereum.FilterQuery{Addresses: []common.Address{receiver}})
+	require.NoError(t, err)
+	msgs := make([]maybe_revert_message_receiver.ClientAny2EVMMessage, len(logs))
+	for i, log := range logs {
+		args, err := receiverABI.Methods["ccipReceive"].Inputs.Unpack(log.Data)
+		require.NoError(t, err)
+		msgs[i] = *abi.ConvertType(args[0], new(maybe_revert_message_receiver.ClientAny2EVMMessage)).(*maybe_revert_message_receiver.ClientAny2EVMMessage)
+	}
+	return msgs
+}
+
+// AssertReceivedData asserts that receiver, as deployed by DeployRecordingReceiver, received the message with
+// seqNum exactly once and that its data is byte for byte want.
+func (c *CCIPContracts) AssertReceivedData(t *testing.T, receiver common.Address, seqNum uint64, want []byte) {
+	received := c.receivedMessage(t, receiver, seqNum)
+	require.Equal(t, hexutil.Encode(want), hexutil.Encode(received.Data), "data of seqNum %d was modified", seqNum)
+}
+
+// receivedMessage returns the message with seqNum as received by receiver, as deployed by DeployRecordingReceiver,
+// which must have received it exactly once.
+func (c *CCIPContracts) receivedMessage(t *testing.T, receiver common.Address, seqNum uint64) maybe_revert_message_receiver.ClientAny2EVMMessage {
+	it, err := c.Dest.OffRamp.FilterExecutionStateChanged(nil, []uint64{seqNum}, nil)
+	require.NoError(t, err)
+	defer it.Close()
+	require.True(t, it.Next(), "seqNum %d was not executed", seqNum)
+	messageID := it.Event.MessageId
+	require.NoError(t, it.Error())
+
+	var received []maybe_revert_message_receiver.ClientAny2EVMMessage
+	for _, msg := range ReceivedMessages(t, c.Dest.Chain, receiver) {
+		if msg.MessageId == messageID {
+			received = append(received, msg)
+		}
+	}
+	require.Len(t, received, 1, "seqNum %d was not received exactly once", seqNum)
+	return received[0]
+}
@@ -0,0 +1,56 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestAssertReceivedData(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	receiver := DeployRecordingReceiver(t, c.Dest.Chain, c.Dest.User)
+	payloads := [][]byte{
+		{},
+		{0},
+		{0, 'a', 0, 0, 'b', 0},
+		append([]byte("trailing zeros"), make([]byte, 32)...),
+		make([]byte, 33),
+	}
+
+	tx, err := c.Source.LinkToken.Approve(c.Source.User, c.Source.Router.Address(), HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	for _, payload := range payloads {
+		c.SendRequest(t, router.ClientEVM2AnyMessage{
+			Receiver:     MustEncodeAddress(t, receiver),
+			Data:         payload,
+			TokenAmounts: []router.ClientEVMTokenAmount{},
+			FeeToken:     c.Source.LinkToken.Address(),
+			ExtraArgs:    extraArgs,
+		})
+	}
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, len(payloads))
+	tree := c.CommitMessages(t, msgs)
+	indices := make([]int, len(msgs))
+	for i := range indices {
+		indices[i] = i
+	}
+	_, err = c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, indices))
+	require.NoError(t, err)
+
+	for i, msg := range msgs {
+		c.AssertExecStateForSeqNum(t, msg.SequenceNumber, abihelpers.ExecutionStateSuccess)
+		c.AssertReceivedData(t, receiver, msg.SequenceNumber, payloads[i])
+	}
+	require.Len(t, ReceivedMessages(t, c.Dest.Chain, receiver), len(payloads))
+}
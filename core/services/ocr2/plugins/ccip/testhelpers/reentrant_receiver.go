@@ -0,0 +1,153 @@
+package testhelpers
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// reentrantReceiverRuntime is the assembly of a receiver armed by any other call than ccipReceive and
+// supportsInterface, whose calldata it stores, length in slot 0 and words after, for ccipReceive to call the offRamp
+// with and revert with what that reverted with. %[1]s is the ccipReceive selector and %[2]s the offRamp address.
+const reentrantReceiverRuntime = `
+PUSH 0
+CALLDATALOAD
+PUSH 224
+SHR
+DUP1
+PUSH 0x01ffc9a7
+EQ
+JUMPI @supportsInterface
+PUSH %[1]s
+EQ
+JUMPI @ccipReceive
+
+CALLDATASIZE
+PUSH 0
+SSTORE
+PUSH 0
+arm:
+CALLDATASIZE
+DUP2
+LT
+ISZERO
+JUMPI @accept
+DUP1
+CALLDATALOAD
+PUSH 32
+DUP3
+DIV
+PUSH 1
+ADD
+SSTORE
+PUSH 32
+ADD
+JUMP @arm
+
+ccipReceive:
+PUSH 0
+SLOAD
+DUP1
+ISZERO
+JUMPI @accept
+PUSH 0
+load:
+DUP2
+DUP2
+LT
+ISZERO
+JUMPI @reenter
+PUSH 32
+DUP2
+DIV
+PUSH 1
+ADD
+SLOAD
+DUP2
+MSTORE
+PUSH 32
+ADD
+JUMP @load
+
+reenter:
+POP
+PUSH 0
+PUSH 0
+SWAP2
+PUSH 0
+PUSH 0
+PUSH %[2]s
+GAS
+CALL
+JUMPI @accept
+RETURNDATASIZE
+PUSH 0
+PUSH 0
+RETURNDATACOPY
+RETURNDATASIZE
+PUSH 0
+REVERT
+
+accept:
+STOP
+` + receiverSupportsInterface
+
+// DeployReentrantReceiver deploys a message receiver that, once armed by ArmReentrantReceiver, calls back into
+// offRamp from ccipReceive while its message is being executed.
+func DeployReentrantReceiver(t *testing.T, chain *backends.SimulatedBackend, owner *bind.TransactOpts, offRamp common.Address) common.Address {
+	return deployReceiver(t, chain, owner, assemble(t, fmt.Sprintf(reentrantReceiverRuntime, ccipReceiveSelector(t), offRamp.Hex())))
+}
+
+// ArmReentrantReceiver arms receiver, as deployed by DeployReentrantReceiver, to manually execute report without gas
+// limit overrides from ccipReceive. The report is expected to contain the message being received, so that the
+// receiver attempts to execute it a second time.
+func (c *CCIPContracts) ArmReentrantReceiver(t *testing.T, receiver common.Address, report evm_2_evm_offramp.InternalExecutionReport) {
+	offRampABI, err := evm_2_evm_offramp.EVM2EVMOffRampMetaData.GetAbi()
+	require.NoError(t, err)
+	gasLimitOverrides := make([]*big.Int, len(report.Messages))
+	for i := range gasLimitOverrides {
+		gasLimitOverrides[i] = big.NewInt(0)
+	}
+	calldata, err := offRampABI.Pack("manuallyExecute", report, gasLimitOverrides)
+	require.NoError(t, err)
+	tx, err := bind.NewBoundContract(receiver, abi.ABI{}, c.Dest.Chain, c.Dest.Chain, c.Dest.Chain).RawTransact(c.Dest.User, calldata)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+}
+
+// AssertReentrancyBlocked asserts that the execution in receipt executed the message with seqNum exactly once and
+// marked it as failed, because the reentrant call its receiver made into the offRamp was rejected with
+// AlreadyExecuted. The offRamp marks a message as in progress before routing it, and only untouched and failed
+// messages can be executed, so the second execution reverts before reaching the receiver again.
+func (c *CCIPContracts) AssertReentrancyBlocked(t *testing.T, receipt *types.Receipt, seqNum uint64) {
+	var executions int
+	for _, log := range receipt.Logs {
+		if log.Address != c.Dest.OffRamp.Address() {
+			continue
+		}
+		stateChanged, err := c.Dest.OffRamp.ParseExecutionStateChanged(*log)
+		if err == nil && stateChanged.SequenceNumber == seqNum {
+			executions++
+		}
+	}
+	require.Equal(t, 1, executions, "seqNum %d was not executed exactly once", seqNum)
+
+	data := c.receiverFailureData(t, receipt, seqNum)
+	AssertErrorSelector(t, data, evm_2_evm_offramp.EVM2EVMOffRampABI, "AlreadyExecuted")
+	offRampABI, err := evm_2_evm_offramp.EVM2EVMOffRampMetaData.GetAbi()
+	require.NoError(t, err)
+	args, err := offRampABI.Errors["AlreadyExecuted"].Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	require.Equal(t, seqNum, args[0])
+	c.AssertExecStateForSeqNum(t, seqNum, abihelpers.ExecutionStateFailure)
+}
@@ -0,0 +1,51 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+func TestReentrantReceiver(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	receiver := DeployReentrantReceiver(t, c.Dest.Chain, c.Dest.User, c.Dest.OffRamp.Address())
+	// Enough gas for the receiver to load the armed call and for the offRamp to verify the report again.
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(1_000_000), false)
+	require.NoError(t, err)
+	tx, err := c.Source.LinkToken.Approve(c.Source.User, c.Source.Router.Address(), HundredLink)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	c.SendRequest(t, router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, receiver),
+		Data:         []byte("reenter"),
+		TokenAmounts: []router.ClientEVMTokenAmount{},
+		FeeToken:     c.Source.LinkToken.Address(),
+		ExtraArgs:    extraArgs,
+	})
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 1)
+	seqNum := msgs[0].SequenceNumber
+	nonce, err := c.Dest.OffRamp.GetSenderNonce(nil, msgs[0].Sender)
+	require.NoError(t, err)
+
+	tree := c.CommitMessages(t, msgs)
+	report := BuildExecutionReport(t, tree, msgs, []int{0})
+	c.ArmReentrantReceiver(t, receiver, report)
+	receipt, err := c.TransmitExecutionReport(t, oracles[0], report)
+	require.NoError(t, err)
+	c.AssertReentrancyBlocked(t, receipt, seqNum)
+
+	after, err := c.Dest.OffRamp.GetSenderNonce(nil, msgs[0].Sender)
+	require.NoError(t, err)
+	require.Equal(t, nonce+1, after, "sender nonce was not incremented exactly once")
+	_, err = c.TransmitExecutionReport(t, oracles[0], report)
+	AssertRevertedWith(t, err, evm_2_evm_offramp.EVM2EVMOffRampABI, "AlreadyAttempted")
+}
@@ -0,0 +1,54 @@
+package testhelpers
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/stretchr/testify/require"
+)
+
+// Reorg replaces all blocks of chain after the block number ancestor with a longer side chain of empty blocks,
+// dropping the transactions that were included in the orphaned blocks.
+func Reorg(t *testing.T, chain *backends.SimulatedBackend, ancestor uint64) {
+	head := chain.Blockchain().CurrentBlock()
+	require.Greater(t, head.Number.Uint64(), ancestor, "nothing to reorg")
+	parent := chain.Blockchain().GetHeaderByNumber(ancestor)
+	require.NotNil(t, parent)
+
+	require.NoError(t, chain.Fork(context.Background(), parent.Hash()))
+	for i := ancestor; i <= head.Number.Uint64(); i++ {
+		chain.Commit()
+	}
+	require.Equal(t, head.Number.Uint64()+1, chain.Blockchain().CurrentBlock().Number.Uint64())
+	require.NotEqual(t, head.Hash(), chain.Blockchain().GetHeaderByNumber(head.Number.Uint64()).Hash(), "chain was not reorged")
+}
+
+// AssertRootRecommittedAfterReorg asserts that root is committed on the canonical dest chain and that exactly one
+// canonical commit of it exists, i.e. the commit that was reorged out has been replaced rather than lost.
+func (c *CCIPContracts) AssertRootRecommittedAfterReorg(t *testing.T, root [32]byte) {
+	timestamp, err := c.Dest.CommitStore.GetMerkleRoot(nil, root)
+	require.NoError(t, err)
+	require.NotZero(t, timestamp.Uint64(), "root is not committed")
+
+	it, err := c.Dest.CommitStore.FilterReportAccepted(&bind.FilterOpts{Start: 0})
+	require.NoError(t, err)
+	defer it.Close()
+	var commits int
+	for it.Next() {
+		if it.Event.Report.MerkleRoot != root {
+			continue
+		}
+		commits++
+		canonical := c.Dest.Chain.Blockchain().GetHeaderByNumber(it.Event.Raw.BlockNumber)
+		require.NotNil(t, canonical)
+		require.Equal(t, canonical.Hash(), it.Event.Raw.BlockHash, "root committed in a non canonical block")
+		block, err := c.Dest.Chain.BlockByHash(context.Background(), it.Event.Raw.BlockHash)
+		require.NoError(t, err)
+		require.Equal(t, new(big.Int).SetUint64(block.Time()).String(), timestamp.String())
+	}
+	require.NoError(t, it.Error())
+	require.Equal(t, 1, commits, "root must be committed exactly once on the canonical chain")
+}
@@ -0,0 +1,39 @@
+package testhelpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// RetryAfterFailure executes report through oracle, which must fail every message of it, runs fixReceiver to make
+// the receivers accept them, and re-executes report, asserting that all its messages then succeed. The DON only
+// executes untouched messages, and transmitting report again reverts with AlreadyAttempted, so the retry is a
+// manual execution. Failed messages can be manually executed right away, without waiting for the permissionless
+// execution threshold, so the retry is made in the next dest block.
+func (c *CCIPContracts) RetryAfterFailure(t *testing.T, oracle SimulatedOracle, report evm_2_evm_offramp.InternalExecutionReport, fixReceiver func()) {
+	_, err := c.TransmitExecutionReport(t, oracle, report)
+	require.NoError(t, err)
+	for _, msg := range report.Messages {
+		c.AssertExecStateForSeqNum(t, msg.SequenceNumber, abihelpers.ExecutionStateFailure)
+	}
+
+	fixReceiver()
+	_, err = c.TransmitExecutionReport(t, oracle, report)
+	AssertRevertedWith(t, err, evm_2_evm_offramp.EVM2EVMOffRampABI, "AlreadyAttempted")
+
+	config, err := c.Dest.OffRamp.GetDynamicConfig(nil)
+	require.NoError(t, err)
+	at := ChainClock(c.Dest.Chain).Add(2 * simulatedBlockInterval * time.Second)
+	require.True(t, at.Before(c.CommittedAt(t, report).Add(time.Duration(config.PermissionLessExecutionThresholdSeconds)*time.Second)),
+		"permissionless execution threshold has passed")
+	_, err = c.ManuallyExecuteAt(t, report, at)
+	require.NoError(t, err)
+	for _, msg := range report.Messages {
+		c.AssertExecStateForSeqNum(t, msg.SequenceNumber, abihelpers.ExecutionStateSuccess)
+	}
+}
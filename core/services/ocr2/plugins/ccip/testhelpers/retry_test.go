@@ -0,0 +1,30 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryAfterFailure(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	receiver := c.Dest.Receivers[0].Receiver
+	tx, err := receiver.SetRevert(c.Dest.User, true)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	c.SendDataMessages(t, 2, big.NewInt(100_000))
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 2)
+	tree := c.CommitMessages(t, msgs)
+
+	c.RetryAfterFailure(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0, 1}), func() {
+		tx, err := receiver.SetRevert(c.Dest.User, false)
+		require.NoError(t, err)
+		ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	})
+}
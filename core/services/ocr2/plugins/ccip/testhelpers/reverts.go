@@ -0,0 +1,52 @@
+package testhelpers
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// RevertData extracts the raw revert data carried by an error returned from the simulated backend,
+// e.g. when gas estimation of a transaction fails because the call reverts.
+func RevertData(t *testing.T, err error) []byte {
+	require.Error(t, err)
+	var dataErr rpc.DataError
+	require.True(t, errors.As(err, &dataErr), "error %q does not carry revert data", err)
+	encoded, ok := dataErr.ErrorData().(string)
+	require.True(t, ok, "unexpected revert data type %T", dataErr.ErrorData())
+	data, err := hexutil.Decode(encoded)
+	require.NoError(t, err)
+	return data
+}
+
+// AssertRevertedWith asserts that err is a revert of the custom error errorName defined in contractABI.
+func AssertRevertedWith(t *testing.T, err error, contractABI string, errorName string) {
+	require.Error(t, err, "expected %s revert", errorName)
+	AssertErrorSelector(t, RevertData(t, err), contractABI, errorName)
+}
+
+// AssertErrorSelector asserts that the given revert data starts with the selector of the custom error
+// errorName defined in contractABI.
+func AssertErrorSelector(t *testing.T, data []byte, contractABI string, errorName string) {
+	parsed, err := abi.JSON(strings.NewReader(contractABI))
+	require.NoError(t, err)
+	abiErr, ok := parsed.Errors[errorName]
+	require.True(t, ok, "error %s not found in abi", errorName)
+	require.GreaterOrEqual(t, len(data), 4, "revert data too short to contain an error selector")
+	require.Equal(t, hexutil.Encode(abiErr.ID[:4]), hexutil.Encode(data[:4]), "expected %s revert, got data %s", errorName, hexutil.Encode(data))
+}
+
+// AssertPanicked asserts that the given revert data is a Panic(uint256) with the given code, with which the
+// compiler reverts on failed assertions and checked arithmetic, e.g. 0x11 for an arithmetic overflow.
+func AssertPanicked(t *testing.T, data []byte, code uint64) {
+	expected := append(crypto.Keccak256([]byte("Panic(uint256)"))[:4], common.BigToHash(new(big.Int).SetUint64(code)).Bytes()...)
+	require.Equal(t, hexutil.Encode(expected), hexutil.Encode(data), "expected panic with code %#x", code)
+}
@@ -0,0 +1,56 @@
+package testhelpers
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// DeployNewDestRouter deploys a router on the dest chain with the wrapped native and ARM proxy of the dest chain,
+// which has no ramps registered yet.
+func (c *CCIPContracts) DeployNewDestRouter(t *testing.T) *router.Router {
+	address, tx, _, err := router.DeployRouter(c.Dest.User, c.Dest.Chain, c.Dest.WrappedNative.Address(), c.Dest.ARMProxy.Address())
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	newRouter, err := router.NewRouter(address, c.Dest.Chain)
+	require.NoError(t, err)
+	return newRouter
+}
+
+// UpgradeDestRouter swaps the dest router of the lane for newRouter, leaving messages in flight untouched. The
+// offRamp is registered on newRouter and removed from the previous router, and pointed to newRouter by setting
+// the exec OCR2 config again with the default onchain and offchain configs, which changes its config digest.
+func (c *CCIPContracts) UpgradeDestRouter(t *testing.T, newRouter *router.Router) {
+	offRamps := []router.RouterOffRamp{{SourceChainSelector: c.Source.ChainSelector, OffRamp: c.Dest.OffRamp.Address()}}
+	tx, err := newRouter.ApplyRampUpdates(c.Dest.User, nil, nil, offRamps)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+	tx, err = c.Dest.Router.ApplyRampUpdates(c.Dest.User, nil, offRamps, nil)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Dest.Chain)
+
+	c.Dest.Router = newRouter
+	c.SetupExecOCR2Config(t, c.CreateDefaultExecOnchainConfig(t), c.CreateDefaultExecOffchainConfig(t))
+	dynamicConfig, err := c.Dest.OffRamp.GetDynamicConfig(nil)
+	require.NoError(t, err)
+	require.Equal(t, newRouter.Address(), dynamicConfig.Router)
+}
+
+// AssertExecutedThroughRouter asserts that the execution in receipt marked the message with seqNum as successful
+// and routed it to its receiver through r, and through no other router.
+func (c *CCIPContracts) AssertExecutedThroughRouter(t *testing.T, receipt *types.Receipt, seqNum uint64, r *router.Router) {
+	var routed bool
+	for _, log := range receipt.Logs {
+		if _, err := r.ParseMessageExecuted(*log); err != nil {
+			continue
+		}
+		require.Equal(t, r.Address(), log.Address, "message routed through another router")
+		routed = true
+	}
+	require.True(t, routed, "message was not routed through %s", r.Address())
+	c.AssertExecStateForSeqNum(t, seqNum, abihelpers.ExecutionStateSuccess)
+}
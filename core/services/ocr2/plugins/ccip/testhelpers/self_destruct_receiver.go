@@ -0,0 +1,54 @@
+package testhelpers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// selfDestructReceiverRuntime is the assembly of a receiver whose ccipReceive self-destructs the receiver,
+// sending its balance to the zero address.
+const selfDestructReceiverRuntime = `
+PUSH 0
+CALLDATALOAD
+PUSH 224
+SHR
+DUP1
+PUSH 0x01ffc9a7
+EQ
+JUMPI @supportsInterface
+PUSH %[1]s
+EQ
+JUMPI @ccipReceive
+PUSH 0
+DUP1
+REVERT
+
+ccipReceive:
+PUSH 0
+SELFDESTRUCT
+` + receiverSupportsInterface
+
+// DeploySelfDestructReceiver deploys a message receiver that accepts the first message it receives by
+// self-destructing. Its code is gone once the execution of that message is mined, after which the address
+// receives messages like any account without code.
+func DeploySelfDestructReceiver(t *testing.T, chain *backends.SimulatedBackend, owner *bind.TransactOpts) common.Address {
+	return deployReceiver(t, chain, owner, assemble(t, fmt.Sprintf(selfDestructReceiverRuntime, ccipReceiveSelector(t))))
+}
+
+// AssertTerminalExecState asserts that the message with seqNum was executed to a terminal state, either
+// SUCCESS or FAILURE, and returns it. Messages left untouched or in progress by an execution fail the assertion.
+func (c *CCIPContracts) AssertTerminalExecState(t *testing.T, seqNum uint64) abihelpers.MessageExecutionState {
+	actual, err := c.Dest.OffRamp.GetExecutionState(nil, seqNum)
+	require.NoError(t, err)
+	state := abihelpers.MessageExecutionState(actual)
+	require.Contains(t, []abihelpers.MessageExecutionState{abihelpers.ExecutionStateSuccess, abihelpers.ExecutionStateFailure}, state,
+		"seqNum %d is in the non-terminal execution state %d", seqNum, state)
+	return state
+}
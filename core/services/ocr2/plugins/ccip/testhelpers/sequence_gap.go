@@ -0,0 +1,48 @@
+package testhelpers
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_onramp"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/internal/ccipdata"
+)
+
+// SimulateSequenceGap returns a source of the send requests of onRamp with sequence numbers in [min, max], as the
+// log poller returns them, in which the request with skipSeq is missing, as if the onRamp had skipped skipSeq. The
+// onRamp assigns sequence numbers from a counter it has no setter for, so a gap cannot be emitted on chain and is
+// forced in what the commit plugin reads instead. For test use only.
+func SimulateSequenceGap(t *testing.T, onRamp *evm_2_evm_onramp.EVM2EVMOnRamp, skipSeq uint64) func(min, max uint64) []ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested] {
+	return func(min, max uint64) []ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested] {
+		it, err := onRamp.FilterCCIPSendRequested(&bind.FilterOpts{})
+		require.NoError(t, err)
+		defer it.Close()
+		var reqs []ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested]
+		for it.Next() {
+			if seqNum := it.Event.Message.SequenceNumber; seqNum >= min && seqNum <= max && seqNum != skipSeq {
+				reqs = append(reqs, ccipdata.Event[evm_2_evm_onramp.EVM2EVMOnRampCCIPSendRequested]{
+					Data:      *it.Event,
+					BlockMeta: ccipdata.BlockMeta{BlockNumber: int64(it.Event.Raw.BlockNumber)},
+				})
+			}
+		}
+		require.NoError(t, it.Error())
+		return reqs
+	}
+}
+
+// AssertCommittedUpToGap asserts that the messages before skipSeq are committed, while neither skipSeq nor any
+// message after it up to lastSeq is, so that no root spans the gap at skipSeq and the commitStore still expects
+// skipSeq next.
+func (c *CCIPContracts) AssertCommittedUpToGap(t *testing.T, skipSeq, lastSeq uint64) {
+	require.Greater(t, skipSeq, uint64(1), "no messages before the gap")
+	require.GreaterOrEqual(t, lastSeq, skipSeq)
+	for seqNum := uint64(1); seqNum < skipSeq; seqNum++ {
+		require.True(t, c.isCommitted(t, seqNum), "seqNum %d before the gap was not committed", seqNum)
+	}
+	for seqNum := skipSeq; seqNum <= lastSeq; seqNum++ {
+		require.False(t, c.isCommitted(t, seqNum), "seqNum %d was committed across the gap", seqNum)
+	}
+}
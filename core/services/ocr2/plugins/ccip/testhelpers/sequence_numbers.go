@@ -0,0 +1,111 @@
+package testhelpers
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_onramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+const (
+	// onRampSequenceNumberOffset is the bit offset of the sequence number counter of the onRamp within its storage
+	// slot, where it is packed after s_nopFeesJuels (uint96) and s_nopWeightsTotal (uint32).
+	onRampSequenceNumberOffset = 128
+	// onRampStorageSlots bounds the storage slots searched for the sequence number counter.
+	onRampStorageSlots = 64
+)
+
+var uint64Mask = new(big.Int).SetUint64(math.MaxUint64)
+
+// onRampSequenceNumberSlot returns the storage slot of the sequence number counter of onRamp, found by matching
+// both the last used sequence number and the accrued NOP fees packed into it.
+func onRampSequenceNumberSlot(t *testing.T, chain *backends.SimulatedBackend, onRamp *evm_2_evm_onramp.EVM2EVMOnRamp) common.Hash {
+	next, err := onRamp.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Greater(t, next, uint64(1), "no message has been sent through the onRamp")
+	nopFees, err := onRamp.GetNopFeesJuels(nil)
+	require.NoError(t, err)
+
+	var slots []common.Hash
+	for i := int64(0); i < onRampStorageSlots; i++ {
+		slot := common.BigToHash(big.NewInt(i))
+		raw, err := chain.StorageAt(context.Background(), onRamp.Address(), slot, nil)
+		require.NoError(t, err)
+		value := new(big.Int).SetBytes(raw)
+		seq := new(big.Int).And(new(big.Int).Rsh(value, onRampSequenceNumberOffset), uint64Mask)
+		fees := new(big.Int).And(value, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 96), big.NewInt(1)))
+		if seq.Uint64() == next-1 && fees.Cmp(nopFees) == 0 {
+			slots = append(slots, slot)
+		}
+	}
+	require.Len(t, slots, 1, "sequence number slot of the onRamp not found")
+	return slots[0]
+}
+
+// SetOnRampSequenceNumber returns a copy of the head state of chain in which seq is the last sequence number used
+// by onRamp, e.g. to push a lane to the end of the sequence number range. The onRamp has no setter for its counter
+// and simulated backends cannot write storage, so the counter is overwritten in a detached state instead, which is
+// only seen by SendOnState calls against it. At least one message must have been sent through onRamp.
+func SetOnRampSequenceNumber(t *testing.T, chain *backends.SimulatedBackend, onRamp *evm_2_evm_onramp.EVM2EVMOnRamp, seq uint64) *state.StateDB {
+	slot := onRampSequenceNumberSlot(t, chain, onRamp)
+	stateDB, err := chain.Blockchain().State()
+	require.NoError(t, err)
+
+	value := stateDB.GetState(onRamp.Address(), slot).Big()
+	value.AndNot(value, new(big.Int).Lsh(uint64Mask, onRampSequenceNumberOffset))
+	value.Or(value, new(big.Int).Lsh(new(big.Int).SetUint64(seq), onRampSequenceNumberOffset))
+	stateDB.SetState(onRamp.Address(), slot, common.BigToHash(value))
+	return stateDB
+}
+
+// SendOnState sends msg from sender through the source router against stateDB, as returned by
+// SetOnRampSequenceNumber, and returns the message sent by the onRamp, or the revert data if the send reverts.
+// The router must be approved on chain for the fee and tokens of msg.
+func (c *CCIPContracts) SendOnState(t *testing.T, stateDB *state.StateDB, sender common.Address, msg router.ClientEVM2AnyMessage) (*evm_2_evm_onramp.InternalEVM2EVMMessage, []byte) {
+	routerABI, err := router.RouterMetaData.GetAbi()
+	require.NoError(t, err)
+	data, err := routerABI.Pack("ccipSend", c.Dest.ChainSelector, msg)
+	require.NoError(t, err)
+
+	to := c.Source.Router.Address()
+	callMsg := &core.Message{
+		From:              sender,
+		To:                &to,
+		Value:             big.NewInt(0),
+		GasLimit:          30_000_000,
+		GasPrice:          big.NewInt(0),
+		GasFeeCap:         big.NewInt(0),
+		GasTipCap:         big.NewInt(0),
+		Data:              data,
+		SkipAccountChecks: true,
+	}
+	blockchain := c.Source.Chain.Blockchain()
+	evm := vm.NewEVM(core.NewEVMBlockContext(blockchain.CurrentHeader(), blockchain, nil), core.NewEVMTxContext(callMsg), stateDB, blockchain.Config(), vm.Config{NoBaseFee: true})
+	stateDB.SetTxContext(common.Hash{}, 0)
+	result, err := core.ApplyMessage(evm, callMsg, new(core.GasPool).AddGas(math.MaxUint64))
+	require.NoError(t, err)
+	if result.Failed() {
+		return nil, result.Revert()
+	}
+
+	for _, log := range stateDB.GetLogs(common.Hash{}, blockchain.CurrentHeader().Number.Uint64(), common.Hash{}) {
+		if log.Address != c.Source.OnRamp.Address() {
+			continue
+		}
+		if sent, err := c.Source.OnRamp.ParseCCIPSendRequested(*log); err == nil {
+			return &sent.Message, nil
+		}
+	}
+	require.Fail(t, "send did not emit CCIPSendRequested")
+	return nil, nil
+}
@@ -0,0 +1,52 @@
+package testhelpers
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+func TestOnRampSequenceNumberBoundary(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	c.SendDataMessages(t, 1, big.NewInt(200_000))
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, c.Dest.Receivers[0].Receiver.Address()),
+		Data:         []byte("hello"),
+		TokenAmounts: []router.ClientEVMTokenAmount{},
+		FeeToken:     c.Source.LinkToken.Address(),
+		ExtraArgs:    extraArgs,
+	}
+	sender := c.Source.User.From
+
+	// Without moving the counter, a send against the detached state matches the next send on chain.
+	stateDB := SetOnRampSequenceNumber(t, c.Source.Chain, c.Source.OnRamp, 1)
+	sent, revert := c.SendOnState(t, stateDB, sender, msg)
+	require.Nil(t, revert)
+	require.Equal(t, uint64(2), sent.SequenceNumber)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+	c.SendRequest(t, msg)
+	onChain := c.SendRequestedMessages(t, startBlock+1)
+	require.Len(t, onChain, 1)
+	require.Equal(t, sent.SequenceNumber, onChain[0].SequenceNumber)
+	require.Equal(t, sent.MessageId, onChain[0].MessageId)
+
+	// The last sequence number is assigned normally, after which sends are rejected rather than wrapping around.
+	stateDB = SetOnRampSequenceNumber(t, c.Source.Chain, c.Source.OnRamp, math.MaxUint64-1)
+	sent, revert = c.SendOnState(t, stateDB, sender, msg)
+	require.Nil(t, revert)
+	require.Equal(t, uint64(math.MaxUint64), sent.SequenceNumber)
+	sent, revert = c.SendOnState(t, stateDB, sender, msg)
+	require.Nil(t, sent)
+	AssertPanicked(t, revert, 0x11)
+
+	// The counter on chain is unaffected.
+	next, err := c.Source.OnRamp.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), next)
+}
@@ -28,6 +28,25 @@ func SetupChain(t *testing.T) (*backends.SimulatedBackend, *bind.TransactOpts) {
 	return chain, user
 }
 
+// NewFundedUser creates a new account on the given chain funded with 10 ETH by funder.
+func NewFundedUser(t *testing.T, chain *backends.SimulatedBackend, funder *bind.TransactOpts) *bind.TransactOpts {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	user, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	require.NoError(t, err)
+
+	nonce, err := chain.PendingNonceAt(context.Background(), funder.From)
+	require.NoError(t, err)
+	gasPrice, err := chain.SuggestGasPrice(context.Background())
+	require.NoError(t, err)
+	tx := ethtypes.NewTransaction(nonce, user.From, new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18)), 21000, gasPrice, nil)
+	signedTx, err := funder.Signer(funder.From, tx)
+	require.NoError(t, err)
+	require.NoError(t, chain.SendTransaction(context.Background(), signedTx))
+	ConfirmTxs(t, []*ethtypes.Transaction{signedTx}, chain)
+	return user
+}
+
 type EthKeyStoreSim struct {
 	ETHKS keystore.Eth
 	CSAKS keystore.CSA
@@ -0,0 +1,99 @@
+package testhelpers
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/smartcontractkit/libocr/offchainreporting2/confighelper"
+	ocr2types "github.com/smartcontractkit/libocr/offchainreporting2plus/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/services/keystore/chaintype"
+	"github.com/smartcontractkit/chainlink/v2/core/services/keystore/keys/ocr2key"
+	"github.com/smartcontractkit/chainlink/v2/core/services/keystore/keys/p2pkey"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// SimulatedOracle is an OCR2 oracle whose keys are held by the test, so that reports can be
+// transmitted to the dest chain without running chainlink nodes.
+type SimulatedOracle struct {
+	KeyBundle   ocr2key.KeyBundle
+	PeerID      string
+	Transmitter *bind.TransactOpts
+}
+
+// Identity returns the identity of the oracle as used when deriving OCR2 configs.
+func (o SimulatedOracle) Identity(t *testing.T) confighelper.OracleIdentityExtra {
+	onchainPublicKey, err := hex.DecodeString(strings.TrimPrefix(o.KeyBundle.OnChainPublicKey(), "0x"))
+	require.NoError(t, err)
+	return confighelper.OracleIdentityExtra{
+		OracleIdentity: confighelper.OracleIdentity{
+			OnchainPublicKey:  onchainPublicKey,
+			TransmitAccount:   ocr2types.Account(o.Transmitter.From.String()),
+			OffchainPublicKey: o.KeyBundle.OffchainPublicKey(),
+			PeerID:            o.PeerID,
+		},
+		ConfigEncryptionPublicKey: o.KeyBundle.ConfigEncryptionPublicKey(),
+	}
+}
+
+// NewSimulatedOracles generates n oracles whose transmitters are funded on chain by funder.
+func NewSimulatedOracles(t *testing.T, chain *backends.SimulatedBackend, funder *bind.TransactOpts, n int) []SimulatedOracle {
+	oracles := make([]SimulatedOracle, n)
+	for i := range oracles {
+		kb, err := ocr2key.New(chaintype.EVM)
+		require.NoError(t, err)
+		p2pKey, err := p2pkey.NewV2()
+		require.NoError(t, err)
+		oracles[i] = SimulatedOracle{
+			KeyBundle:   kb,
+			PeerID:      p2pKey.PeerID().Raw(),
+			Transmitter: NewFundedUser(t, chain, funder),
+		}
+	}
+	return oracles
+}
+
+// SetupSimulatedOracles makes four simulated oracles both the commit and the exec DON of the lane,
+// using the default onchain and offchain configs.
+func (c *CCIPContracts) SetupSimulatedOracles(t *testing.T) []SimulatedOracle {
+	oracles := NewSimulatedOracles(t, c.Dest.Chain, c.Dest.User, 4)
+	c.Oracles = make([]confighelper.OracleIdentityExtra, len(oracles))
+	for i, oracle := range oracles {
+		c.Oracles[i] = oracle.Identity(t)
+	}
+	c.SetupOnchainConfig(t,
+		c.CreateDefaultCommitOnchainConfig(t),
+		c.CreateDefaultCommitOffchainConfig(t),
+		c.CreateDefaultExecOnchainConfig(t),
+		c.CreateDefaultExecOffchainConfig(t),
+	)
+	return oracles
+}
+
+// TransmitExecutionReport transmits report to the offRamp as the given oracle. The offRamp does not verify
+// report signatures, so none are attached. The transmit error is passed to the caller.
+func (c *CCIPContracts) TransmitExecutionReport(t *testing.T, oracle SimulatedOracle, report evm_2_evm_offramp.InternalExecutionReport) (*types.Receipt, error) {
+	encoded, err := abihelpers.EncodeExecutionReport(report)
+	require.NoError(t, err)
+	configDetails, err := c.Dest.OffRamp.LatestConfigDetails(nil)
+	require.NoError(t, err)
+	// Epoch 1, round 0. Only the config digest is checked by the offRamp.
+	reportContext := [3][32]byte{configDetails.ConfigDigest, abihelpers.EvmWord(1 << 8), {}}
+
+	tx, err := c.Dest.OffRamp.Transmit(oracle.Transmitter, reportContext, encoded, nil, nil, [32]byte{})
+	if err != nil {
+		return nil, err
+	}
+	c.Dest.Chain.Commit()
+	rec, err := bind.WaitMined(context.Background(), c.Dest.Chain, tx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), rec.Status, "execution report transmission reverted")
+	return rec, nil
+}
@@ -0,0 +1,63 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/price_registry"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+)
+
+// GetFeeWithStaleGasPrice ages the gas price of the chain with destSelector in the source price registry one second
+// past the staleness threshold of the registry and quotes msg for it through the source router. The price of the
+// fee token of msg is refreshed at its current value after aging, so that only the gas price is stale. The error
+// returned by the router is passed to the caller.
+func (c *CCIPContracts) GetFeeWithStaleGasPrice(t *testing.T, destSelector uint64, msg router.ClientEVM2AnyMessage) (*big.Int, error) {
+	threshold, err := c.Source.PriceRegistry.GetStalenessThreshold(nil)
+	require.NoError(t, err)
+	gasPrice, err := c.Source.PriceRegistry.GetDestinationChainGasPrice(nil, destSelector)
+	require.NoError(t, err)
+	require.NotZero(t, gasPrice.Timestamp, "no gas price for chain %d", destSelector)
+	stale := time.Unix(int64(gasPrice.Timestamp)+threshold.Int64()+1, 0)
+	if stale.After(ChainClock(c.Source.Chain)) {
+		SetChainClock(t, c.Source.Chain, stale)
+	}
+
+	feeToken := msg.FeeToken
+	if feeToken == (common.Address{}) {
+		feeToken = c.Source.WrappedNative.Address()
+	}
+	price, err := c.Source.PriceRegistry.GetTokenPrice(nil, feeToken)
+	require.NoError(t, err)
+	// Token updates without a dest chain selector leave the gas prices untouched.
+	tx, err := c.Source.PriceRegistry.UpdatePrices(c.Source.User, tokenPriceUpdate(feeToken, price.Value))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+
+	return c.Source.Router.GetFee(&bind.CallOpts{From: c.Source.User.From}, destSelector, msg)
+}
+
+// AssertFeeQuoteRejectsStaleGasPrice asserts that the source router neither quotes nor sends msg to the chain with
+// destSelector once its gas price is stale. The registry has no fallback for stale gas prices: both revert with
+// StaleGasPrice, reporting the threshold and the time passed since the last update, which exceeds it.
+func (c *CCIPContracts) AssertFeeQuoteRejectsStaleGasPrice(t *testing.T, destSelector uint64, msg router.ClientEVM2AnyMessage) {
+	_, err := c.GetFeeWithStaleGasPrice(t, destSelector, msg)
+	AssertRevertedWith(t, err, price_registry.PriceRegistryABI, "StaleGasPrice")
+	data := RevertData(t, err)
+	registryABI, err := price_registry.PriceRegistryMetaData.GetAbi()
+	require.NoError(t, err)
+	args, err := registryABI.Errors["StaleGasPrice"].Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	require.Equal(t, destSelector, args[0])
+	threshold, timePassed := args[1].(*big.Int), args[2].(*big.Int)
+	require.Positive(t, timePassed.Cmp(threshold), "gas price quoted as stale within the threshold")
+
+	_, err = c.Source.Router.CcipSend(c.Source.User, destSelector, msg)
+	AssertRevertedWith(t, err, price_registry.PriceRegistryABI, "StaleGasPrice")
+}
@@ -0,0 +1,35 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/price_registry"
+)
+
+func TestFeeQuoteWithStaleGasPrice(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	msg := gasLimitMessage(t, c.Source.User, big.NewInt(200_000))
+	freshFee, err := c.Source.Router.GetFee(&bind.CallOpts{From: c.Source.User.From}, c.Dest.ChainSelector, msg)
+	require.NoError(t, err)
+
+	c.AssertFeeQuoteRejectsStaleGasPrice(t, c.Dest.ChainSelector, msg)
+
+	// Refreshing the gas price at its previous value restores the previous quote.
+	gasPrice, err := c.Source.PriceRegistry.GetDestinationChainGasPrice(nil, c.Dest.ChainSelector)
+	require.NoError(t, err)
+	tx, err := c.Source.PriceRegistry.UpdatePrices(c.Source.User, price_registry.InternalPriceUpdates{
+		TokenPriceUpdates: []price_registry.InternalTokenPriceUpdate{},
+		DestChainSelector: c.Dest.ChainSelector,
+		UsdPerUnitGas:     gasPrice.Value,
+	})
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	fee, err := c.Source.Router.GetFee(&bind.CallOpts{From: c.Source.User.From}, c.Dest.ChainSelector, msg)
+	require.NoError(t, err)
+	require.Equal(t, freshFee.String(), fee.String())
+}
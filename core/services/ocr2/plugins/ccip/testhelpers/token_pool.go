@@ -0,0 +1,293 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_onramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/lock_release_token_pool"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// DeploySourcePool deploys a new lock release pool for the source LINK token, allowing the current onRamp to call it.
+// A non-empty allowList enables the pool's original sender allowList, which cannot be enabled after deployment.
+func (c *CCIPContracts) DeploySourcePool(t *testing.T, allowList []common.Address) *lock_release_token_pool.LockReleaseTokenPool {
+	poolAddress, _, _, err := lock_release_token_pool.DeployLockReleaseTokenPool(
+		c.Source.User,
+		c.Source.Chain,
+		c.Source.LinkToken.Address(),
+		allowList,
+		c.Source.ARMProxy.Address(),
+		true,
+	)
+	require.NoError(t, err)
+	c.Source.Chain.Commit()
+	pool, err := lock_release_token_pool.NewLockReleaseTokenPool(poolAddress, c.Source.Chain)
+	require.NoError(t, err)
+
+	_, err = pool.ApplyRampUpdates(c.Source.User, []lock_release_token_pool.TokenPoolRampUpdate{
+		{
+			Ramp:    c.Source.OnRamp.Address(),
+			Allowed: true,
+			RateLimiterConfig: lock_release_token_pool.RateLimiterConfig{
+				IsEnabled: true,
+				Capacity:  HundredLink,
+				Rate:      big.NewInt(1e18),
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+	c.Source.Chain.Commit()
+	return pool
+}
+
+// ReplaceSourcePool points the onRamp at pool for the source LINK token and makes it the lane's source pool.
+func (c *CCIPContracts) ReplaceSourcePool(t *testing.T, pool *lock_release_token_pool.LockReleaseTokenPool) {
+	_, err := c.Source.OnRamp.ApplyPoolUpdates(c.Source.User,
+		[]evm_2_evm_onramp.InternalPoolUpdate{{Token: c.Source.LinkToken.Address(), Pool: c.Source.Pool.Address()}},
+		[]evm_2_evm_onramp.InternalPoolUpdate{{Token: c.Source.LinkToken.Address(), Pool: pool.Address()}},
+	)
+	require.NoError(t, err)
+	c.Source.Chain.Commit()
+	c.Source.Pool = pool
+}
+
+// ConfigurePoolAllowlist sets the original senders allowed to lock or burn through pool to exactly senders.
+func (c *CCIPContracts) ConfigurePoolAllowlist(t *testing.T, pool *lock_release_token_pool.LockReleaseTokenPool, owner *bind.TransactOpts, senders []common.Address) {
+	enabled, err := pool.GetAllowListEnabled(nil)
+	require.NoError(t, err)
+	require.True(t, enabled, "pool was deployed without an allowList")
+
+	current, err := pool.GetAllowList(nil)
+	require.NoError(t, err)
+	allowed := make(map[common.Address]bool, len(senders))
+	for _, sender := range senders {
+		allowed[sender] = true
+	}
+	var removes []common.Address
+	for _, addr := range current {
+		if !allowed[addr] {
+			removes = append(removes, addr)
+		}
+	}
+
+	tx, err := pool.ApplyAllowListUpdates(owner, removes, senders)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+
+	allowList, err := pool.GetAllowList(nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, senders, allowList)
+}
+
+// FundSourceLink transfers amount of source LINK from the lane owner to addr.
+func (c *CCIPContracts) FundSourceLink(t *testing.T, addr common.Address, amount *big.Int) {
+	tx, err := c.Source.LinkToken.Transfer(c.Source.User, addr, amount)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+}
+
+// SendTokenFrom sends amount of source LINK from sender to the first dest receiver, paying fees in LINK.
+// The sender must hold enough LINK to cover both. The error returned by ccipSend is passed to the caller.
+func (c *CCIPContracts) SendTokenFrom(t *testing.T, sender *bind.TransactOpts, amount *big.Int) (*types.Transaction, error) {
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver: MustEncodeAddress(t, c.Dest.Receivers[0].Receiver.Address()),
+		Data:     []byte{},
+		TokenAmounts: []router.ClientEVMTokenAmount{
+			{
+				Token:  c.Source.LinkToken.Address(),
+				Amount: amount,
+			},
+		},
+		FeeToken:  c.Source.LinkToken.Address(),
+		ExtraArgs: extraArgs,
+	}
+	fee, err := c.Source.Router.GetFee(nil, c.Dest.ChainSelector, msg)
+	require.NoError(t, err)
+	tx, err := c.Source.LinkToken.Approve(sender, c.Source.Router.Address(), new(big.Int).Add(fee, amount))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+
+	tx, err = c.Source.Router.CcipSend(sender, c.Dest.ChainSelector, msg)
+	if err != nil {
+		return nil, err
+	}
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+	return tx, nil
+}
+
+// SendTokenExpectingNotAllowed asserts that sending amount of source LINK from sender reverts
+// because sender is not on the source pool's allowList.
+func (c *CCIPContracts) SendTokenExpectingNotAllowed(t *testing.T, sender *bind.TransactOpts, amount *big.Int) {
+	_, err := c.SendTokenFrom(t, sender, amount)
+	AssertRevertedWith(t, err, lock_release_token_pool.LockReleaseTokenPoolABI, "SenderNotAllowed")
+}
+
+// PausedPool holds the ramps PausePool revoked from Pool, along with their rate limiter configs.
+type PausedPool struct {
+	Pool     *lock_release_token_pool.LockReleaseTokenPool
+	OnRamps  []lock_release_token_pool.TokenPoolRampUpdate
+	OffRamps []lock_release_token_pool.TokenPoolRampUpdate
+}
+
+// PausePool stops all transfers through pool. Pools have no pause of their own, so their owner pauses them by
+// revoking every onRamp and offRamp, after which both lockOrBurn and releaseOrMint revert with PermissionsError.
+// The revoked ramps are returned so that UnpausePool can restore them.
+func PausePool(t *testing.T, chain *backends.SimulatedBackend, pool *lock_release_token_pool.LockReleaseTokenPool, owner *bind.TransactOpts) PausedPool {
+	paused := PausedPool{Pool: pool}
+	paused.OnRamps, paused.OffRamps = poolRamps(t, pool)
+	require.NotZero(t, len(paused.OnRamps)+len(paused.OffRamps), "pool has no ramps to pause")
+
+	tx, err := pool.ApplyRampUpdates(owner, revokedRamps(paused.OnRamps), revokedRamps(paused.OffRamps))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+	return paused
+}
+
+// poolRamps returns updates allowing each of the current onRamps and offRamps of pool with its rate limiter config.
+func poolRamps(t *testing.T, pool *lock_release_token_pool.LockReleaseTokenPool) (onRampUpdates, offRampUpdates []lock_release_token_pool.TokenPoolRampUpdate) {
+	onRamps, err := pool.GetOnRamps(nil)
+	require.NoError(t, err)
+	for _, onRamp := range onRamps {
+		bucket, err := pool.CurrentOnRampRateLimiterState(nil, onRamp)
+		require.NoError(t, err)
+		onRampUpdates = append(onRampUpdates, lock_release_token_pool.TokenPoolRampUpdate{Ramp: onRamp, Allowed: true,
+			RateLimiterConfig: lock_release_token_pool.RateLimiterConfig{IsEnabled: bucket.IsEnabled, Capacity: bucket.Capacity, Rate: bucket.Rate}})
+	}
+	offRamps, err := pool.GetOffRamps(nil)
+	require.NoError(t, err)
+	for _, offRamp := range offRamps {
+		bucket, err := pool.CurrentOffRampRateLimiterState(nil, offRamp)
+		require.NoError(t, err)
+		offRampUpdates = append(offRampUpdates, lock_release_token_pool.TokenPoolRampUpdate{Ramp: offRamp, Allowed: true,
+			RateLimiterConfig: lock_release_token_pool.RateLimiterConfig{IsEnabled: bucket.IsEnabled, Capacity: bucket.Capacity, Rate: bucket.Rate}})
+	}
+	return onRampUpdates, offRampUpdates
+}
+
+// revokedRamps returns updates revoking each of ramps.
+func revokedRamps(ramps []lock_release_token_pool.TokenPoolRampUpdate) []lock_release_token_pool.TokenPoolRampUpdate {
+	revoked := make([]lock_release_token_pool.TokenPoolRampUpdate, len(ramps))
+	for i, ramp := range ramps {
+		revoked[i] = lock_release_token_pool.TokenPoolRampUpdate{Ramp: ramp.Ramp, Allowed: false,
+			RateLimiterConfig: lock_release_token_pool.RateLimiterConfig{Capacity: big.NewInt(0), Rate: big.NewInt(0)}}
+	}
+	return revoked
+}
+
+// UnpausePool restores the ramps revoked by PausePool with their previous rate limiter configs. Their rate limit
+// buckets start out full again.
+func UnpausePool(t *testing.T, chain *backends.SimulatedBackend, paused PausedPool, owner *bind.TransactOpts) {
+	tx, err := paused.Pool.ApplyRampUpdates(owner, paused.OnRamps, paused.OffRamps)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+}
+
+// SendTokenExpectingPoolPaused asserts that sending amount of source LINK from sender reverts because the source
+// pool is paused.
+func (c *CCIPContracts) SendTokenExpectingPoolPaused(t *testing.T, sender *bind.TransactOpts, amount *big.Int) {
+	_, err := c.SendTokenFrom(t, sender, amount)
+	AssertRevertedWith(t, err, lock_release_token_pool.LockReleaseTokenPoolABI, "PermissionsError")
+}
+
+// ExecuteExpectingPoolPaused transmits report, which must execute a single token transfer, and asserts that the
+// message fails gracefully because the dest pool is paused: the report is accepted, but the message is marked as
+// failed with a TokenHandlingError wrapping the PermissionsError of the pool.
+func (c *CCIPContracts) ExecuteExpectingPoolPaused(t *testing.T, oracle SimulatedOracle, report evm_2_evm_offramp.InternalExecutionReport) {
+	require.Len(t, report.Messages, 1)
+	seqNum := report.Messages[0].SequenceNumber
+	receipt, err := c.TransmitExecutionReport(t, oracle, report)
+	require.NoError(t, err)
+
+	var returnData []byte
+	for _, log := range receipt.Logs {
+		if log.Address != c.Dest.OffRamp.Address() {
+			continue
+		}
+		stateChanged, err := c.Dest.OffRamp.ParseExecutionStateChanged(*log)
+		if err == nil && stateChanged.SequenceNumber == seqNum {
+			returnData = stateChanged.ReturnData
+		}
+	}
+	c.AssertExecStateForSeqNum(t, seqNum, abihelpers.ExecutionStateFailure)
+
+	AssertErrorSelector(t, returnData, evm_2_evm_offramp.EVM2EVMOffRampABI, "TokenHandlingError")
+	offRampABI, err := evm_2_evm_offramp.EVM2EVMOffRampMetaData.GetAbi()
+	require.NoError(t, err)
+	unpacked, err := offRampABI.Errors["TokenHandlingError"].Inputs.Unpack(returnData[4:])
+	require.NoError(t, err)
+	require.Len(t, unpacked, 1)
+	AssertErrorSelector(t, unpacked[0].([]byte), lock_release_token_pool.LockReleaseTokenPoolABI, "PermissionsError")
+}
+
+// MigrateTokenPool replaces oldPool with newPool, which must hold the same token, and moves all tokens held by
+// oldPool to newPool. The ramps of oldPool are allowed on newPool with their rate limiter configs and revoked on
+// oldPool, and every onRamp and offRamp of oldPool is rewired to newPool for token, the source token the ramps map
+// to their pools. Pools cannot hand out the tokens locked by senders, only liquidity added by providers, so owner
+// drains oldPool by allowing itself as an offRamp without a rate limit just long enough to release the full balance
+// to newPool. Provider balances are not migrated. The migrated balance is asserted to have arrived in newPool.
+func MigrateTokenPool(t *testing.T, chain *backends.SimulatedBackend, oldPool, newPool *lock_release_token_pool.LockReleaseTokenPool, owner *bind.TransactOpts, token common.Address) {
+	poolToken, err := oldPool.GetToken(nil)
+	require.NoError(t, err)
+	newPoolToken, err := newPool.GetToken(nil)
+	require.NoError(t, err)
+	require.Equal(t, poolToken, newPoolToken, "pools hold different tokens")
+	migrated := GetBalance(t, chain, poolToken, oldPool.Address())
+	newBalanceBefore := GetBalance(t, chain, poolToken, newPool.Address())
+
+	onRamps, offRamps := poolRamps(t, oldPool)
+	require.NotZero(t, len(onRamps)+len(offRamps), "pool has no ramps to migrate")
+	ownerRamp := lock_release_token_pool.TokenPoolRampUpdate{Ramp: owner.From, Allowed: true,
+		RateLimiterConfig: lock_release_token_pool.RateLimiterConfig{Capacity: big.NewInt(0), Rate: big.NewInt(0)}}
+	tx, err := newPool.ApplyRampUpdates(owner, onRamps, offRamps)
+	require.NoError(t, err)
+	tx2, err := oldPool.ApplyRampUpdates(owner, revokedRamps(onRamps), append(revokedRamps(offRamps), ownerRamp))
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx, tx2}, chain)
+	tx, err = oldPool.ReleaseOrMint(owner, nil, newPool.Address(), migrated, 0, nil)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+	tx, err = oldPool.ApplyRampUpdates(owner, nil, revokedRamps([]lock_release_token_pool.TokenPoolRampUpdate{ownerRamp}))
+	require.NoError(t, err)
+
+	txs := []*types.Transaction{tx}
+	for _, ramp := range onRamps {
+		onRamp, err := evm_2_evm_onramp.NewEVM2EVMOnRamp(ramp.Ramp, chain)
+		require.NoError(t, err)
+		tx, err = onRamp.ApplyPoolUpdates(owner,
+			[]evm_2_evm_onramp.InternalPoolUpdate{{Token: token, Pool: oldPool.Address()}},
+			[]evm_2_evm_onramp.InternalPoolUpdate{{Token: token, Pool: newPool.Address()}},
+		)
+		require.NoError(t, err)
+		txs = append(txs, tx)
+	}
+	for _, ramp := range offRamps {
+		offRamp, err := evm_2_evm_offramp.NewEVM2EVMOffRamp(ramp.Ramp, chain)
+		require.NoError(t, err)
+		tx, err = offRamp.ApplyPoolUpdates(owner,
+			[]evm_2_evm_offramp.InternalPoolUpdate{{Token: token, Pool: oldPool.Address()}},
+			[]evm_2_evm_offramp.InternalPoolUpdate{{Token: token, Pool: newPool.Address()}},
+		)
+		require.NoError(t, err)
+		txs = append(txs, tx)
+	}
+	ConfirmTxs(t, txs, chain)
+
+	require.Zero(t, GetBalance(t, chain, poolToken, oldPool.Address()).Sign(), "tokens were stranded in the old pool")
+	require.Equal(t, new(big.Int).Add(newBalanceBefore, migrated).String(), GetBalance(t, chain, poolToken, newPool.Address()).String())
+	oldOnRamps, err := oldPool.GetOnRamps(nil)
+	require.NoError(t, err)
+	oldOffRamps, err := oldPool.GetOffRamps(nil)
+	require.NoError(t, err)
+	require.Empty(t, append(oldOnRamps, oldOffRamps...), "old pool can still be called by ramps")
+}
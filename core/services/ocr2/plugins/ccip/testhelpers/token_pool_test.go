@@ -0,0 +1,114 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/lock_release_token_pool"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+func TestPoolAllowlist(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	listed := NewFundedUser(t, c.Source.Chain, c.Source.User)
+	unlisted := NewFundedUser(t, c.Source.Chain, c.Source.User)
+	c.FundSourceLink(t, listed.From, Link(10))
+	c.FundSourceLink(t, unlisted.From, Link(10))
+
+	// The allowList can only be enabled at deploy time, so seed it with the lane owner and then reconfigure it.
+	pool := c.DeploySourcePool(t, []common.Address{c.Source.User.From})
+	c.ReplaceSourcePool(t, pool)
+	c.ConfigurePoolAllowlist(t, pool, c.Source.User, []common.Address{listed.From})
+
+	_, err := c.SendTokenFrom(t, listed, Link(1))
+	require.NoError(t, err)
+	require.Equal(t, Link(1).String(), c.GetSourceLinkBalance(t, pool.Address()).String())
+
+	c.SendTokenExpectingNotAllowed(t, unlisted, Link(1))
+	c.SendTokenExpectingNotAllowed(t, c.Source.User, Link(1))
+	require.Equal(t, Link(1).String(), c.GetSourceLinkBalance(t, pool.Address()).String())
+}
+
+func TestPausedPool(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	receiver := c.Dest.Receivers[0].Receiver.Address()
+
+	// Sends fail while the source pool is paused, and resume once it is unpaused.
+	paused := PausePool(t, c.Source.Chain, c.Source.Pool, c.Source.User)
+	c.SendTokenExpectingPoolPaused(t, c.Source.User, Link(1))
+	UnpausePool(t, c.Source.Chain, paused, c.Source.User)
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	_, err := c.SendTokenFrom(t, c.Source.User, Link(1))
+	require.NoError(t, err)
+	_, err = c.SendTokenFrom(t, c.Source.User, Link(2))
+	require.NoError(t, err)
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 2)
+	tree := c.CommitMessages(t, msgs)
+
+	// Executions fail gracefully while the dest pool is paused, and resume once it is unpaused.
+	balanceBefore := GetBalance(t, c.Dest.Chain, c.Dest.LinkToken.Address(), receiver)
+	paused = PausePool(t, c.Dest.Chain, c.Dest.Pool, c.Dest.User)
+	c.ExecuteExpectingPoolPaused(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0}))
+	require.Equal(t, balanceBefore.String(), GetBalance(t, c.Dest.Chain, c.Dest.LinkToken.Address(), receiver).String())
+	UnpausePool(t, c.Dest.Chain, paused, c.Dest.User)
+	_, err = c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{1}))
+	require.NoError(t, err)
+	c.AssertExecStateForSeqNum(t, msgs[1].SequenceNumber, abihelpers.ExecutionStateSuccess)
+	require.Equal(t, new(big.Int).Add(balanceBefore, Link(2)).String(), GetBalance(t, c.Dest.Chain, c.Dest.LinkToken.Address(), receiver).String())
+}
+
+func TestMigrateTokenPool(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	receiver := c.Dest.Receivers[0].Receiver.Address()
+	sourceToken := c.Source.LinkToken.Address()
+
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64() + 1
+	_, err := c.SendTokenFrom(t, c.Source.User, Link(5))
+	require.NoError(t, err)
+	msgs := c.SendRequestedMessages(t, startBlock)
+	require.Len(t, msgs, 1)
+	locked := c.GetSourceLinkBalance(t, c.Source.Pool.Address())
+	require.GreaterOrEqual(t, locked.Cmp(Link(5)), 0)
+
+	newSourcePool := deployLockReleasePool(t, c.Source.Chain, c.Source.User, sourceToken, c.Source.ARMProxy.Address())
+	MigrateTokenPool(t, c.Source.Chain, c.Source.Pool, newSourcePool, c.Source.User, sourceToken)
+	require.Equal(t, locked.String(), c.GetSourceLinkBalance(t, newSourcePool.Address()).String())
+	c.Source.Pool = newSourcePool
+
+	newDestPool := deployLockReleasePool(t, c.Dest.Chain, c.Dest.User, c.Dest.LinkToken.Address(), c.Dest.ARMProxy.Address())
+	liquidity := GetBalance(t, c.Dest.Chain, c.Dest.LinkToken.Address(), c.Dest.Pool.Address())
+	MigrateTokenPool(t, c.Dest.Chain, c.Dest.Pool, newDestPool, c.Dest.User, sourceToken)
+	c.Dest.Pool = newDestPool
+
+	// The message sent before the migration is released from the new dest pool.
+	tree := c.CommitMessages(t, msgs)
+	balanceBefore := GetBalance(t, c.Dest.Chain, c.Dest.LinkToken.Address(), receiver)
+	_, err = c.TransmitExecutionReport(t, oracles[0], BuildExecutionReport(t, tree, msgs, []int{0}))
+	require.NoError(t, err)
+	c.AssertExecStateForSeqNum(t, msgs[0].SequenceNumber, abihelpers.ExecutionStateSuccess)
+	require.Equal(t, new(big.Int).Add(balanceBefore, Link(5)).String(), GetBalance(t, c.Dest.Chain, c.Dest.LinkToken.Address(), receiver).String())
+	require.Equal(t, new(big.Int).Sub(liquidity, Link(5)).String(), GetBalance(t, c.Dest.Chain, c.Dest.LinkToken.Address(), newDestPool.Address()).String())
+
+	// New sends lock into the new source pool.
+	_, err = c.SendTokenFrom(t, c.Source.User, Link(1))
+	require.NoError(t, err)
+	require.Equal(t, new(big.Int).Add(locked, Link(1)).String(), c.GetSourceLinkBalance(t, newSourcePool.Address()).String())
+}
+
+func deployLockReleasePool(t *testing.T, chain *backends.SimulatedBackend, owner *bind.TransactOpts, token common.Address, armProxy common.Address) *lock_release_token_pool.LockReleaseTokenPool {
+	poolAddress, tx, _, err := lock_release_token_pool.DeployLockReleaseTokenPool(owner, chain, token, nil, armProxy, true)
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, chain)
+	pool, err := lock_release_token_pool.NewLockReleaseTokenPool(poolAddress, chain)
+	require.NoError(t, err)
+	return pool
+}
@@ -0,0 +1,120 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/generated/link_token_interface"
+)
+
+// TrafficSpec describes the traffic sent by GenerateMixedTraffic. Messages are built deterministically by
+// cycling through the data sizes, tokens and fee tokens, so that a spec always produces the same mix.
+type TrafficSpec struct {
+	// Blocks is the number of source blocks the traffic is spread over, each of which includes
+	// MessagesPerBlock messages.
+	Blocks           int
+	MessagesPerBlock int
+	// DataSizes are the payload sizes in bytes of consecutive messages.
+	DataSizes []int
+	// Every TokenEvery-th message transfers TokenAmount of the next of Tokens. Zero sends data-only messages.
+	TokenEvery  int
+	Tokens      []common.Address
+	TokenAmount *big.Int
+	// FeeTokens are the tokens paying for consecutive messages.
+	FeeTokens []common.Address
+}
+
+// Messages returns the number of messages sent for spec.
+func (spec TrafficSpec) Messages() int {
+	return spec.Blocks * spec.MessagesPerBlock
+}
+
+// message returns the i-th message of the traffic, sent to receiver.
+func (spec TrafficSpec) message(t *testing.T, i int, receiver common.Address) router.ClientEVM2AnyMessage {
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, receiver),
+		Data:         make([]byte, spec.DataSizes[i%len(spec.DataSizes)]),
+		TokenAmounts: []router.ClientEVMTokenAmount{},
+		FeeToken:     spec.FeeTokens[i%len(spec.FeeTokens)],
+		ExtraArgs:    extraArgs,
+	}
+	if spec.TokenEvery > 0 && (i+1)%spec.TokenEvery == 0 {
+		token := spec.Tokens[(i/spec.TokenEvery)%len(spec.Tokens)]
+		msg.TokenAmounts = append(msg.TokenAmounts, router.ClientEVMTokenAmount{Token: token, Amount: spec.TokenAmount})
+	}
+	return msg
+}
+
+// GenerateMixedTraffic sends the messages described by spec from sender to the first dest receiver, mining
+// one source block per spec.MessagesPerBlock messages, and returns their sequence numbers in sending order.
+// The router is approved for the tokens and fees of all messages up front, which sender must hold.
+func (c *CCIPContracts) GenerateMixedTraffic(t *testing.T, sender *bind.TransactOpts, spec TrafficSpec) []uint64 {
+	require.Positive(t, spec.Blocks)
+	require.Positive(t, spec.MessagesPerBlock)
+	require.NotEmpty(t, spec.DataSizes)
+	require.NotEmpty(t, spec.FeeTokens)
+	if spec.TokenEvery > 0 {
+		require.NotEmpty(t, spec.Tokens)
+		require.NotNil(t, spec.TokenAmount)
+	}
+
+	receiver := c.Dest.Receivers[0].Receiver.Address()
+	msgs := make([]router.ClientEVM2AnyMessage, spec.Messages())
+	allowances := make(map[common.Address]*big.Int)
+	addAllowance := func(token common.Address, amount *big.Int) {
+		if allowances[token] == nil {
+			allowances[token] = big.NewInt(0)
+		}
+		allowances[token].Add(allowances[token], amount)
+	}
+	for i := range msgs {
+		msgs[i] = spec.message(t, i, receiver)
+		fee, err := c.Source.Router.GetFee(nil, c.Dest.ChainSelector, msgs[i])
+		require.NoError(t, err)
+		addAllowance(msgs[i].FeeToken, fee)
+		for _, tokenAmount := range msgs[i].TokenAmounts {
+			addAllowance(tokenAmount.Token, tokenAmount.Amount)
+		}
+	}
+	var txs []*types.Transaction
+	for token, allowance := range allowances {
+		erc20, err := link_token_interface.NewLinkToken(token, c.Source.Chain)
+		require.NoError(t, err)
+		tx, err := erc20.Approve(sender, c.Source.Router.Address(), allowance)
+		require.NoError(t, err)
+		txs = append(txs, tx)
+	}
+	ConfirmTxs(t, txs, c.Source.Chain)
+
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+	for block := 0; block < spec.Blocks; block++ {
+		txs = txs[:0]
+		for _, msg := range msgs[block*spec.MessagesPerBlock : (block+1)*spec.MessagesPerBlock] {
+			tx, err := c.Source.Router.CcipSend(sender, c.Dest.ChainSelector, msg)
+			require.NoError(t, err)
+			txs = append(txs, tx)
+		}
+		ConfirmTxs(t, txs, c.Source.Chain)
+	}
+
+	it, err := c.Source.OnRamp.FilterCCIPSendRequested(&bind.FilterOpts{Start: startBlock})
+	require.NoError(t, err)
+	defer it.Close()
+	var seqNums []uint64
+	for it.Next() {
+		if it.Event.Message.Sender == sender.From {
+			seqNums = append(seqNums, it.Event.Message.SequenceNumber)
+		}
+	}
+	require.NoError(t, it.Error())
+	require.Len(t, seqNums, len(msgs))
+	return seqNums
+}
@@ -0,0 +1,70 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateMixedTraffic(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	c.Source.User.Value = HundredLink
+	tx, err := c.Source.WrappedNative.Deposit(c.Source.User)
+	c.Source.User.Value = nil
+	require.NoError(t, err)
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+
+	link, weth := c.Source.LinkToken.Address(), c.Source.WrappedNative.Address()
+	spec := TrafficSpec{
+		Blocks:           3,
+		MessagesPerBlock: 4,
+		DataSizes:        []int{0, 32, 1000},
+		TokenEvery:       3,
+		Tokens:           []common.Address{link, weth},
+		TokenAmount:      big.NewInt(1e18),
+		FeeTokens:        []common.Address{link, weth},
+	}
+	startBlock := c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()
+	seqNums := c.GenerateMixedTraffic(t, c.Source.User, spec)
+	require.Len(t, seqNums, spec.Messages())
+	for i := range seqNums {
+		require.Equal(t, seqNums[0]+uint64(i), seqNums[i])
+	}
+
+	it, err := c.Source.OnRamp.FilterCCIPSendRequested(&bind.FilterOpts{Start: startBlock})
+	require.NoError(t, err)
+	defer it.Close()
+	blocks := make(map[uint64]int)
+	var dataOnly, withTokens int
+	tokens := make(map[common.Address]int)
+	feeTokens := make(map[common.Address]int)
+	sizes := make(map[int]int)
+	for it.Next() {
+		blocks[it.Event.Raw.BlockNumber]++
+		msg := it.Event.Message
+		if len(msg.TokenAmounts) == 0 {
+			dataOnly++
+		} else {
+			withTokens++
+			require.Len(t, msg.TokenAmounts, 1)
+			tokens[msg.TokenAmounts[0].Token]++
+		}
+		feeTokens[msg.FeeToken]++
+		sizes[len(msg.Data)]++
+	}
+	require.NoError(t, it.Error())
+
+	require.Len(t, blocks, spec.Blocks)
+	for _, n := range blocks {
+		require.Equal(t, spec.MessagesPerBlock, n)
+	}
+	require.Equal(t, 8, dataOnly)
+	require.Equal(t, 4, withTokens)
+	require.Equal(t, map[common.Address]int{link: 2, weth: 2}, tokens)
+	require.Equal(t, map[common.Address]int{link: 6, weth: 6}, feeTokens)
+	require.Equal(t, map[int]int{0: 4, 32: 4, 1000: 4}, sizes)
+}
@@ -0,0 +1,88 @@
+package testhelpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/smartcontractkit/libocr/offchainreporting2plus/chains/evmutil"
+	ocr2types "github.com/smartcontractkit/libocr/offchainreporting2plus/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// CommitReportTimestamp returns the timestamp of round round of epoch epoch under the current config of the dest
+// commitStore.
+func (c *CCIPContracts) CommitReportTimestamp(t *testing.T, epoch uint32, round uint8) ocr2types.ReportTimestamp {
+	configDetails, err := c.Dest.CommitStore.LatestConfigDetails(nil)
+	require.NoError(t, err)
+	return ocr2types.ReportTimestamp{ConfigDigest: configDetails.ConfigDigest, Epoch: epoch, Round: round}
+}
+
+// TransmitCommitReportAt transmits report from transmitter to the dest commitStore at reportTimestamp, signed by the
+// commit DON made up of oracles, as set up by SetupSimulatedOracles, and returns the receipt. The gas limit is
+// fixed, so that a transmission the commitStore reverts lands on chain like any other.
+func (c *CCIPContracts) TransmitCommitReportAt(t *testing.T, oracles []SimulatedOracle, transmitter SimulatedOracle, reportTimestamp ocr2types.ReportTimestamp, report []byte) *types.Receipt {
+	reportCtx := ocr2types.ReportContext{ReportTimestamp: reportTimestamp}
+	rs, ss, vs := c.signCommitReport(t, oracles, reportCtx, report)
+	opts := *transmitter.Transmitter
+	opts.GasLimit = 1_000_000
+	tx, err := c.Dest.CommitStore.Transmit(&opts, evmutil.RawReportContext(reportCtx), report, rs, ss, vs)
+	require.NoError(t, err)
+	c.Dest.Chain.Commit()
+	receipt, err := bind.WaitMined(context.Background(), c.Dest.Chain, tx)
+	require.NoError(t, err)
+	return receipt
+}
+
+// AssertSingleTransmission asserts that exactly one transaction was sent to the dest commitStore from startBlock on,
+// by one of oracles, and that it committed report. Reverted transactions count as well, so a report transmitted by
+// more than one oracle fails the assertion. The oracle that transmitted the report is returned.
+func (c *CCIPContracts) AssertSingleTransmission(t *testing.T, oracles []SimulatedOracle, startBlock uint64, report []byte) SimulatedOracle {
+	decoded, err := abihelpers.DecodeCommitReport(report)
+	require.NoError(t, err)
+	var senders []common.Address
+	for n := startBlock; n <= c.Dest.Chain.Blockchain().CurrentBlock().Number.Uint64(); n++ {
+		block := c.Dest.Chain.Blockchain().GetBlockByNumber(n)
+		for _, tx := range block.Transactions() {
+			if tx.To() == nil || *tx.To() != c.Dest.CommitStore.Address() {
+				continue
+			}
+			sender, err2 := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+			require.NoError(t, err2)
+			receipt, err2 := c.Dest.Chain.TransactionReceipt(context.Background(), tx.Hash())
+			require.NoError(t, err2)
+			require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, "transmission by %s reverted", sender)
+			senders = append(senders, sender)
+		}
+	}
+	require.Len(t, senders, 1, "report was transmitted by %v", senders)
+	next, err := c.Dest.CommitStore.GetExpectedNextSequenceNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, decoded.Interval.Max+1, next, "report was not committed")
+	for _, oracle := range oracles {
+		if oracle.Transmitter.From == senders[0] {
+			return oracle
+		}
+	}
+	require.FailNow(t, "report was not transmitted by an oracle", "sender %s", senders[0])
+	return SimulatedOracle{}
+}
+
+// signCommitReport signs report in reportCtx by the commit DON made up of oracles, as set up by
+// SetupSimulatedOracles. The commitStore takes f+1 signatures, which the first oracles provide.
+func (c *CCIPContracts) signCommitReport(t *testing.T, oracles []SimulatedOracle, reportCtx ocr2types.ReportContext, report []byte) (rs, ss [][32]byte, vs [32]byte) {
+	require.NotNil(t, c.commitOCRConfig, "commit DON is not configured")
+	require.Greater(t, len(oracles), int(c.commitOCRConfig.F))
+	for i, oracle := range oracles[:c.commitOCRConfig.F+1] {
+		signature, err := oracle.KeyBundle.Sign(reportCtx, report)
+		require.NoError(t, err)
+		r, s, v, err := evmutil.SplitSignature(signature)
+		require.NoError(t, err)
+		rs, ss, vs[i] = append(rs, r), append(ss, s), v
+	}
+	return rs, ss, vs
+}
@@ -0,0 +1,70 @@
+package testhelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/evm_2_evm_offramp"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/router"
+	"github.com/smartcontractkit/chainlink/v2/core/services/ocr2/plugins/ccip/abihelpers"
+)
+
+// SendZeroTokenAmountExpectingSuccess sends a message from sender to the first dest receiver that lists token with
+// a zero amount, paying the fee in native tokens, and delivers it through oracle. Pools lock and release zero
+// amounts like any other, so the message is asserted to be sent, executed successfully and to reach the receiver
+// without moving any tokens on either chain.
+func (c *CCIPContracts) SendZeroTokenAmountExpectingSuccess(t *testing.T, oracle SimulatedOracle, sender *bind.TransactOpts, token common.Address) {
+	receiver := c.Dest.Receivers[0].Receiver.Address()
+	sourcePool, err := c.Source.OnRamp.GetPoolBySourceToken(nil, token)
+	require.NoError(t, err)
+	destToken, err := c.Dest.OffRamp.GetDestinationToken(nil, token)
+	require.NoError(t, err)
+	destPool, err := c.Dest.OffRamp.GetPoolBySourceToken(nil, token)
+	require.NoError(t, err)
+	senderBefore := GetBalance(t, c.Source.Chain, token, sender.From)
+	sourcePoolBefore := GetBalance(t, c.Source.Chain, token, sourcePool)
+	receiverBefore := GetBalance(t, c.Dest.Chain, destToken, receiver)
+	destPoolBefore := GetBalance(t, c.Dest.Chain, destToken, destPool)
+
+	extraArgs, err := GetEVMExtraArgsV1(big.NewInt(200_000), false)
+	require.NoError(t, err)
+	msg := router.ClientEVM2AnyMessage{
+		Receiver:     MustEncodeAddress(t, receiver),
+		Data:         []byte{},
+		TokenAmounts: []router.ClientEVMTokenAmount{{Token: token, Amount: big.NewInt(0)}},
+		FeeToken:     common.Address{},
+		ExtraArgs:    extraArgs,
+	}
+	fee, err := c.Source.Router.GetFee(nil, c.Dest.ChainSelector, msg)
+	require.NoError(t, err)
+	opts := *sender
+	opts.Value = fee
+	tx, err := c.Source.Router.CcipSend(&opts, c.Dest.ChainSelector, msg)
+	require.NoError(t, err, "sending a zero token amount reverted")
+	ConfirmTxs(t, []*types.Transaction{tx}, c.Source.Chain)
+
+	var sent []evm_2_evm_offramp.InternalEVM2EVMMessage
+	for _, m := range c.SendRequestedMessages(t, c.Source.Chain.Blockchain().CurrentBlock().Number.Uint64()) {
+		if m.Sender == sender.From {
+			sent = append(sent, m)
+		}
+	}
+	require.Len(t, sent, 1)
+	require.Len(t, sent[0].TokenAmounts, 1)
+	require.Equal(t, token, sent[0].TokenAmounts[0].Token)
+	require.Zero(t, sent[0].TokenAmounts[0].Amount.Sign())
+	require.Equal(t, senderBefore.String(), GetBalance(t, c.Source.Chain, token, sender.From).String())
+	require.Equal(t, sourcePoolBefore.String(), GetBalance(t, c.Source.Chain, token, sourcePool).String())
+
+	tree := c.CommitMessages(t, sent)
+	_, err = c.TransmitExecutionReport(t, oracle, BuildExecutionReport(t, tree, sent, []int{0}))
+	require.NoError(t, err)
+	c.AssertExecStateForSeqNum(t, sent[0].SequenceNumber, abihelpers.ExecutionStateSuccess)
+	require.Equal(t, receiverBefore.String(), GetBalance(t, c.Dest.Chain, destToken, receiver).String())
+	require.Equal(t, destPoolBefore.String(), GetBalance(t, c.Dest.Chain, destToken, destPool).String())
+}
@@ -0,0 +1,11 @@
+package testhelpers
+
+import (
+	"testing"
+)
+
+func TestSendZeroTokenAmount(t *testing.T) {
+	c := SetupCCIPContracts(t, SourceChainID, SourceChainSelector, DestChainID, DestChainSelector)
+	oracles := c.SetupSimulatedOracles(t)
+	c.SendZeroTokenAmountExpectingSuccess(t, oracles[0], c.Source.User, c.Source.LinkToken.Address())
+}
@@ -0,0 +1,12 @@
+package v2
+
+import (
+	"github.com/smartcontractkit/chainlink/v2/core/services/job"
+)
+
+// ReadyToFulfill reports whether a listener running j with nodeMinConfs would fulfill req on its first try at
+// latestHead.
+func ReadyToFulfill(j job.Job, req RandomWordsRequested, nodeMinConfs uint32, latestHead uint64) bool {
+	lsn := &listenerV2{respCount: map[string]uint64{}, job: j}
+	return lsn.ready(pendingRequest{confirmedAtBlock: lsn.getConfirmedAt(req, nodeMinConfs), req: req}, latestHead)
+}
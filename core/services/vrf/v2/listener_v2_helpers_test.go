@@ -8,6 +8,9 @@ import (
 
 	"github.com/smartcontractkit/chainlink/v2/core/assets"
 	"github.com/smartcontractkit/chainlink/v2/core/services/vrf/v2"
+	"github.com/smartcontractkit/chainlink/v2/core/services/vrf/vrfcommon"
+	"github.com/smartcontractkit/chainlink/v2/core/services/vrf/vrftesthelpers"
+	"github.com/smartcontractkit/chainlink/v2/core/testdata/testspecs"
 )
 
 func TestListener_EstimateFeeJuels(t *testing.T) {
@@ -29,3 +32,46 @@ func TestListener_EstimateFeeJuels(t *testing.T) {
 	require.Nil(t, actual)
 	require.Error(t, err)
 }
+
+func TestListener_V2PlusConfirmationGating(t *testing.T) {
+	tests := []struct {
+		name          string
+		nodeMinConfs  uint32
+		wantConfirmed uint64
+	}{
+		// The request asks for 5 confirmations, which the job delays by 2.
+		{name: "requested confirmations", nodeMinConfs: 3, wantConfirmed: 7},
+		{name: "node minimum confirmations", nodeMinConfs: 10, wantConfirmed: 10},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			j, err := vrfcommon.ValidatedVRFSpec(testspecs.GenerateVRFSpec(testspecs.VRFSpecParams{
+				RequestedConfsDelay: 2,
+			}).Toml())
+			require.NoError(t, err)
+			c := vrftesthelpers.NewVRFV2PlusContracts(t)
+			subID := vrftesthelpers.CreateSubscription(t, c, assets.Ether(10).ToInt())
+			requestID := vrftesthelpers.RequestRandomnessWithConfirmations(t, c, subID, 5)
+			req := vrftesthelpers.FindRandomWordsRequest(t, c, requestID)
+			head := func() uint64 { return c.Backend.Blockchain().CurrentBlock().Number.Uint64() }
+
+			for head() < req.Raw.BlockNumber+tc.wantConfirmed-1 {
+				require.False(t, v2.ReadyToFulfill(j, v2.NewV2PlusRandomWordsRequested(req), tc.nodeMinConfs, head()),
+					"fulfilled after %d confirmations", head()-req.Raw.BlockNumber)
+				c.Backend.Commit()
+			}
+			require.False(t, v2.ReadyToFulfill(j, v2.NewV2PlusRandomWordsRequested(req), tc.nodeMinConfs, head()))
+			pending, err := c.Coordinator.PendingRequestExists(nil, subID)
+			require.NoError(t, err)
+			require.True(t, pending)
+
+			c.Backend.Commit()
+			require.True(t, v2.ReadyToFulfill(j, v2.NewV2PlusRandomWordsRequested(req), tc.nodeMinConfs, head()))
+			_, err = vrftesthelpers.FulfillRandomWords(t, c, requestID)
+			require.NoError(t, err)
+			word, err := c.Consumer.GetRandomness(nil, requestID, big.NewInt(0))
+			require.NoError(t, err)
+			require.NotZero(t, word.Sign())
+		})
+	}
+}
@@ -0,0 +1,428 @@
+package vrftesthelpers
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/generated/blockhash_store"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/generated/link_token_interface"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/generated/mock_v3_aggregator_contract"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/generated/vrf_consumer_v2_plus_upgradeable_example"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/generated/vrf_coordinator_v2plus"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/generated/vrfv2plus_consumer_example"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/services/keystore/keys/vrfkey"
+	"github.com/smartcontractkit/chainlink/v2/core/services/signatures/secp256k1"
+	"github.com/smartcontractkit/chainlink/v2/core/services/vrf/proof"
+	v2 "github.com/smartcontractkit/chainlink/v2/core/services/vrf/v2"
+)
+
+// VRFV2PlusContracts is a VRF V2Plus coordinator and consumer deployed on a simulated backend. The proving key
+// is held by the test, so that requests can be fulfilled without running a node.
+type VRFV2PlusContracts struct {
+	Backend     *backends.SimulatedBackend
+	LinkToken   *link_token_interface.LinkToken
+	Coordinator *vrf_coordinator_v2plus.VRFCoordinatorV2Plus
+	Consumer    *vrfv2plus_consumer_example.VRFV2PlusConsumerExample
+	Key         vrfkey.KeyV2
+	KeyHash     [32]byte
+
+	// Cast of participants
+	Owner  *bind.TransactOpts // Owns the coordinator, the consumer, subscriptions and all the LINK
+	Oracle *bind.TransactOpts // Registered for the proving key, sends fulfillments
+}
+
+// NewVRFV2PlusContracts deploys and configures a VRF V2Plus coordinator with a single registered proving key,
+// and a consumer with a starting balance of 100 LINK.
+func NewVRFV2PlusContracts(t *testing.T) VRFV2PlusContracts {
+	var (
+		owner  = testutils.MustNewSimTransactor(t)
+		oracle = testutils.MustNewSimTransactor(t)
+	)
+	// Fulfillments are paid for at the gas price they are sent at, which is fixed so that their payments do not
+	// drift down with the base fee of the mostly empty simulated blocks.
+	oracle.GasPrice = assets.GWei(1).ToInt()
+	genesisData := core.GenesisAlloc{
+		owner.From:  {Balance: assets.Ether(1000).ToInt()},
+		oracle.From: {Balance: assets.Ether(1000).ToInt()},
+	}
+	backend := cltest.NewSimulatedBackend(t, genesisData, uint32(ethconfig.Defaults.Miner.GasCeil))
+
+	linkAddress, _, _, err := link_token_interface.DeployLinkToken(owner, backend)
+	require.NoError(t, err, "failed to deploy link contract to simulated ethereum blockchain")
+	linkEthFeed, _, _, err := mock_v3_aggregator_contract.DeployMockV3AggregatorContract(
+		owner, backend, 18, WeiPerUnitLink.BigInt()) // 0.01 eth per link
+	require.NoError(t, err)
+	bhsAddress, _, _, err := blockhash_store.DeployBlockhashStore(owner, backend)
+	require.NoError(t, err, "failed to deploy BlockhashStore contract to simulated ethereum blockchain")
+	coordinatorAddress, _, _, err := vrf_coordinator_v2plus.DeployVRFCoordinatorV2Plus(owner, backend, bhsAddress)
+	require.NoError(t, err, "failed to deploy VRFCoordinatorV2Plus contract to simulated ethereum blockchain")
+	backend.Commit()
+	// Bind to the deployed contracts again, so that the wrappers know their addresses.
+	linkContract, err := link_token_interface.NewLinkToken(linkAddress, backend)
+	require.NoError(t, err)
+	coordinatorContract, err := vrf_coordinator_v2plus.NewVRFCoordinatorV2Plus(coordinatorAddress, backend)
+	require.NoError(t, err)
+
+	_, err = coordinatorContract.SetLINKAndLINKETHFeed(owner, linkAddress, linkEthFeed)
+	require.NoError(t, err)
+	_, err = coordinatorContract.SetConfig(owner,
+		uint16(1),                             // minRequestConfirmations
+		uint32(2.5e6),                         // gas limit
+		uint32(60*60*24),                      // stalenessSeconds
+		uint32(v2.GasAfterPaymentCalculation), // gasAfterPaymentCalculation
+		big.NewInt(1e16),                      // 0.01 eth per link fallbackLinkPrice
+		vrf_coordinator_v2plus.VRFCoordinatorV2PlusFeeConfig{
+			FulfillmentFlatFeeLinkPPM: uint32(1000), // 0.001 LINK premium
+			FulfillmentFlatFeeEthPPM:  uint32(5),    // 0.000005 ETH premium
+		},
+	)
+	require.NoError(t, err, "failed to set coordinator configuration")
+
+	key, err := vrfkey.NewV2()
+	require.NoError(t, err)
+	p, err := key.PublicKey.Point()
+	require.NoError(t, err)
+	x, y := secp256k1.Coordinates(p)
+	_, err = coordinatorContract.RegisterProvingKey(owner, oracle.From, [2]*big.Int{x, y})
+	require.NoError(t, err)
+	backend.Commit()
+	keyHash, err := coordinatorContract.HashOfKey(nil, [2]*big.Int{x, y})
+	require.NoError(t, err)
+
+	consumerAddress, _, _, err := vrfv2plus_consumer_example.DeployVRFV2PlusConsumerExample(
+		owner, backend, coordinatorAddress, linkAddress)
+	require.NoError(t, err, "failed to deploy VRFConsumer contract to simulated ethereum blockchain")
+	backend.Commit()
+	consumerContract, err := vrfv2plus_consumer_example.NewVRFV2PlusConsumerExample(consumerAddress, backend)
+	require.NoError(t, err)
+	_, err = linkContract.Transfer(owner, consumerAddress, assets.Ether(100).ToInt()) // Actually, LINK
+	require.NoError(t, err, "failed to send LINK to VRFConsumer contract on simulated ethereum blockchain")
+	backend.Commit()
+
+	return VRFV2PlusContracts{
+		Backend:     backend,
+		LinkToken:   linkContract,
+		Coordinator: coordinatorContract,
+		Consumer:    consumerContract,
+		Key:         key,
+		KeyHash:     keyHash,
+		Owner:       owner,
+		Oracle:      oracle,
+	}
+}
+
+// CreateSubscription creates a subscription owned by the owner, funds it with fundingJuels of LINK and makes it
+// the subscription of the consumer.
+func CreateSubscription(t *testing.T, c VRFV2PlusContracts, fundingJuels *big.Int) *big.Int {
+	tx, err := c.Coordinator.CreateSubscription(c.Owner)
+	require.NoError(t, err)
+	var subID *big.Int
+	for _, log := range mustConfirm(t, c.Backend, tx).Logs {
+		if created, err2 := c.Coordinator.ParseSubscriptionCreated(*log); err2 == nil {
+			subID = created.SubId
+		}
+	}
+	require.NotNil(t, subID, "no SubscriptionCreated log")
+
+	_, err = c.LinkToken.TransferAndCall(c.Owner, c.Coordinator.Address(), fundingJuels, common.LeftPadBytes(subID.Bytes(), 32))
+	require.NoError(t, err)
+	_, err = c.Coordinator.AddConsumer(c.Owner, subID, c.Consumer.Address())
+	require.NoError(t, err)
+	tx, err = c.Consumer.SetSubId(c.Owner, subID)
+	require.NoError(t, err)
+	mustConfirm(t, c.Backend, tx)
+	return subID
+}
+
+// RequestRandomWords requests numWords random words through the consumer, which must be using subID.
+// The RandomWordsRequested log of the request is returned.
+func RequestRandomWords(t *testing.T, c VRFV2PlusContracts, subID *big.Int, minConf uint16, callbackGasLimit uint32, numWords uint32) *vrf_coordinator_v2plus.VRFCoordinatorV2PlusRandomWordsRequested {
+	consumerSubID, err := c.Consumer.SSubId(nil)
+	require.NoError(t, err)
+	require.Equal(t, subID.String(), consumerSubID.String(), "consumer is not using subscription %s", subID)
+
+	tx, err := c.Consumer.RequestRandomWords(c.Owner, callbackGasLimit, minConf, numWords, c.KeyHash, false)
+	require.NoError(t, err)
+	for _, log := range mustConfirm(t, c.Backend, tx).Logs {
+		if req, err2 := c.Coordinator.ParseRandomWordsRequested(*log); err2 == nil {
+			return req
+		}
+	}
+	require.FailNow(t, "no RandomWordsRequested log")
+	return nil
+}
+
+// FindRandomWordsRequest returns the RandomWordsRequested log of the request with the given id.
+func FindRandomWordsRequest(t *testing.T, c VRFV2PlusContracts, requestID *big.Int) *vrf_coordinator_v2plus.VRFCoordinatorV2PlusRandomWordsRequested {
+	it, err := c.Coordinator.FilterRandomWordsRequested(nil, nil, nil, nil)
+	require.NoError(t, err)
+	defer it.Close()
+	for it.Next() {
+		if it.Event.RequestId.Cmp(requestID) == 0 {
+			return it.Event
+		}
+	}
+	require.NoError(t, it.Error())
+	require.FailNow(t, "request not found", "request %s", requestID)
+	return nil
+}
+
+// FulfillRandomWords fulfills the request with the given id as the oracle would. The coordinator does not check
+// the confirmations of the request, which is left to the listener, so neither does this. Errors returned by the
+// coordinator are passed to the caller.
+func FulfillRandomWords(t *testing.T, c VRFV2PlusContracts, requestID *big.Int) (*gethtypes.Receipt, error) {
+	req := FindRandomWordsRequest(t, c, requestID)
+	preSeed, err := proof.BigToSeed(req.PreSeed)
+	require.NoError(t, err)
+	header, err := c.Backend.HeaderByNumber(context.Background(), new(big.Int).SetUint64(req.Raw.BlockNumber))
+	require.NoError(t, err)
+	preSeedData := proof.PreSeedDataV2Plus{
+		PreSeed:          preSeed,
+		BlockHash:        header.Hash(),
+		BlockNum:         req.Raw.BlockNumber,
+		SubId:            req.SubId,
+		CallbackGasLimit: req.CallbackGasLimit,
+		NumWords:         req.NumWords,
+		Sender:           req.Sender,
+		ExtraArgs:        req.ExtraArgs,
+	}
+	vrfProof, err := c.Key.GenerateProof(proof.FinalSeedV2Plus(preSeedData))
+	require.NoError(t, err)
+	onChainProof, rc, err := proof.GenerateProofResponseFromProofV2Plus(vrfProof, preSeedData)
+	require.NoError(t, err)
+
+	tx, err := c.Coordinator.FulfillRandomWords(c.Oracle, onChainProof, rc)
+	if err != nil {
+		return nil, err
+	}
+	return mustConfirm(t, c.Backend, tx), nil
+}
+
+// FindRandomWordsFulfillment returns the RandomWordsFulfilled log of the request with the given id.
+func FindRandomWordsFulfillment(t *testing.T, c VRFV2PlusContracts, requestID *big.Int) *vrf_coordinator_v2plus.VRFCoordinatorV2PlusRandomWordsFulfilled {
+	it, err := c.Coordinator.FilterRandomWordsFulfilled(nil, []*big.Int{requestID}, nil)
+	require.NoError(t, err)
+	defer it.Close()
+	if it.Next() {
+		return it.Event
+	}
+	require.NoError(t, it.Error())
+	require.FailNow(t, "fulfillment not found", "request %s", requestID)
+	return nil
+}
+
+// DeployCallbackGasConsumer deploys a consumer using subID that records the gas left at the start of its callback,
+// which AssertCallbackGasRespected checks. The consumer only accepts the fulfillment of its latest request.
+func DeployCallbackGasConsumer(t *testing.T, c VRFV2PlusContracts, subID *big.Int) *vrf_consumer_v2_plus_upgradeable_example.VRFConsumerV2PlusUpgradeableExample {
+	address, tx, _, err := vrf_consumer_v2_plus_upgradeable_example.DeployVRFConsumerV2PlusUpgradeableExample(c.Owner, c.Backend)
+	require.NoError(t, err)
+	mustConfirm(t, c.Backend, tx)
+	consumer, err := vrf_consumer_v2_plus_upgradeable_example.NewVRFConsumerV2PlusUpgradeableExample(address, c.Backend)
+	require.NoError(t, err)
+	tx, err = consumer.Initialize(c.Owner, c.Coordinator.Address(), c.LinkToken.Address())
+	require.NoError(t, err)
+	mustConfirm(t, c.Backend, tx)
+	tx, err = c.Coordinator.AddConsumer(c.Owner, subID, address)
+	require.NoError(t, err)
+	mustConfirm(t, c.Backend, tx)
+	return consumer
+}
+
+// RequestCallbackGas requests numWords random words through consumer, deployed by DeployCallbackGasConsumer, and
+// returns the RandomWordsRequested log of the request.
+func RequestCallbackGas(t *testing.T, c VRFV2PlusContracts, consumer *vrf_consumer_v2_plus_upgradeable_example.VRFConsumerV2PlusUpgradeableExample, subID *big.Int, callbackGasLimit uint32, numWords uint32) *vrf_coordinator_v2plus.VRFCoordinatorV2PlusRandomWordsRequested {
+	tx, err := consumer.RequestRandomness(c.Owner, c.KeyHash, subID, 1, callbackGasLimit, numWords)
+	require.NoError(t, err)
+	for _, log := range mustConfirm(t, c.Backend, tx).Logs {
+		if req, err2 := c.Coordinator.ParseRandomWordsRequested(*log); err2 == nil {
+			return req
+		}
+	}
+	require.FailNow(t, "no RandomWordsRequested log")
+	return nil
+}
+
+// callbackGasOverhead bounds the gas the callback gas consumer spends on decoding the random words and checking the
+// request id before it records the gas left.
+const callbackGasOverhead = 20_000
+
+// AssertCallbackGasRespected asserts that the fulfillment of the request with the given id, made by consumer,
+// gave its callback the limit gas it asked for, less what the consumer spends before recording the gas left. That
+// is only recorded by a callback that succeeds, so the fulfillment must have succeeded.
+func AssertCallbackGasRespected(t *testing.T, c VRFV2PlusContracts, consumer *vrf_consumer_v2_plus_upgradeable_example.VRFConsumerV2PlusUpgradeableExample, requestID *big.Int, limit uint32) {
+	req := FindRandomWordsRequest(t, c, requestID)
+	require.Equal(t, limit, req.CallbackGasLimit)
+	require.True(t, FindRandomWordsFulfillment(t, c, requestID).Success, "callback of request %s failed", requestID)
+	gasAvailable, err := consumer.SGasAvailable(nil)
+	require.NoError(t, err)
+	require.LessOrEqual(t, gasAvailable.Uint64(), uint64(limit), "callback was given more gas than its limit")
+	require.Greater(t, gasAvailable.Uint64(), uint64(limit)-callbackGasOverhead, "callback was given less gas than its limit")
+}
+
+// RequestRandomnessWithConfirmations requests a single random word through the consumer, which must be using subID,
+// to be fulfilled only after minConf confirmations. It returns the request id.
+func RequestRandomnessWithConfirmations(t *testing.T, c VRFV2PlusContracts, subID *big.Int, minConf uint16) *big.Int {
+	req := RequestRandomWords(t, c, subID, minConf, 500_000, 1)
+	require.Equal(t, minConf, req.MinimumRequestConfirmations)
+	return req.RequestId
+}
+
+// CancelSubscription cancels subID as its owner, refunding its balance to to, and returns the refunded LINK.
+// The refund is asserted against the balances of the subscription before cancellation, and the LINK and ETH
+// balances of to are asserted to increase by exactly the refunded amounts, so to should not be the owner,
+// who pays for the cancellation. Subscriptions with pending requests cannot be cancelled, which
+// AssertCancelBlockedByPendingRequest covers, so those have to be fulfilled first.
+func CancelSubscription(t *testing.T, c VRFV2PlusContracts, subID *big.Int, to common.Address) *big.Int {
+	pending, err := c.Coordinator.PendingRequestExists(nil, subID)
+	require.NoError(t, err)
+	require.False(t, pending, "subscription %s has pending requests, which block its cancellation", subID)
+	sub, err := c.Coordinator.GetSubscription(nil, subID)
+	require.NoError(t, err)
+	linkBefore, err := c.LinkToken.BalanceOf(nil, to)
+	require.NoError(t, err)
+	ethBefore, err := c.Backend.BalanceAt(context.Background(), to, nil)
+	require.NoError(t, err)
+
+	tx, err := c.Coordinator.CancelSubscription(c.Owner, subID, to)
+	require.NoError(t, err)
+	var canceled *vrf_coordinator_v2plus.VRFCoordinatorV2PlusSubscriptionCanceled
+	for _, log := range mustConfirm(t, c.Backend, tx).Logs {
+		if event, err2 := c.Coordinator.ParseSubscriptionCanceled(*log); err2 == nil {
+			canceled = event
+		}
+	}
+	require.NotNil(t, canceled, "no SubscriptionCanceled log")
+	require.Equal(t, subID.String(), canceled.SubId.String())
+	require.Equal(t, to, canceled.To)
+	require.Equal(t, sub.Balance.String(), canceled.AmountLink.String(), "refund does not match the LINK balance of the subscription")
+	require.Equal(t, sub.EthBalance.String(), canceled.AmountEth.String(), "refund does not match the ETH balance of the subscription")
+
+	linkAfter, err := c.LinkToken.BalanceOf(nil, to)
+	require.NoError(t, err)
+	require.Equal(t, new(big.Int).Add(linkBefore, canceled.AmountLink).String(), linkAfter.String())
+	ethAfter, err := c.Backend.BalanceAt(context.Background(), to, nil)
+	require.NoError(t, err)
+	require.Equal(t, new(big.Int).Add(ethBefore, canceled.AmountEth).String(), ethAfter.String())
+	_, err = c.Coordinator.GetSubscription(nil, subID)
+	require.Error(t, err, "subscription %s still exists after cancellation", subID)
+	return canceled.AmountLink
+}
+
+// AssertCancelBlockedByPendingRequest asserts that subID, which must have a pending request, cannot be cancelled,
+// reverting with PendingRequestExists, and keeps its balance.
+func AssertCancelBlockedByPendingRequest(t *testing.T, c VRFV2PlusContracts, subID *big.Int, to common.Address) {
+	require.Positive(t, PendingRequestCount(t, c, subID), "subscription %s has no pending request", subID)
+	before, err := c.Coordinator.GetSubscription(nil, subID)
+	require.NoError(t, err)
+
+	_, err = c.Coordinator.CancelSubscription(c.Owner, subID, to)
+	require.Error(t, err, "cancellation with a pending request should revert")
+	requireCoordinatorRevert(t, err, "PendingRequestExists")
+	c.Backend.Commit()
+	after, err := c.Coordinator.GetSubscription(nil, subID)
+	require.NoError(t, err)
+	require.Equal(t, before.Balance.String(), after.Balance.String())
+	require.Equal(t, before.EthBalance.String(), after.EthBalance.String())
+}
+
+// PendingRequestCount returns the number of requests of subID that are not fulfilled yet. The coordinator does not
+// count them, but keeps a commitment for every request until it is fulfilled, which PendingRequestExists checks
+// for across the consumers and proving keys of the subscription. The count is asserted to agree with it.
+func PendingRequestCount(t *testing.T, c VRFV2PlusContracts, subID *big.Int) uint64 {
+	it, err := c.Coordinator.FilterRandomWordsRequested(nil, nil, []*big.Int{subID}, nil)
+	require.NoError(t, err)
+	defer it.Close()
+	var count uint64
+	for it.Next() {
+		commitment, err2 := c.Coordinator.SRequestCommitments(nil, it.Event.RequestId)
+		require.NoError(t, err2)
+		if commitment != [32]byte{} {
+			count++
+		}
+	}
+	require.NoError(t, it.Error())
+
+	pending, err := c.Coordinator.PendingRequestExists(nil, subID)
+	require.NoError(t, err)
+	require.Equal(t, count > 0, pending, "%d pending requests counted for subscription %s", count, subID)
+	return count
+}
+
+// requireCoordinatorRevert requires err to be a revert of the coordinator with the custom error errorName.
+func requireCoordinatorRevert(t *testing.T, err error, errorName string) {
+	coordinatorABI, err2 := vrf_coordinator_v2plus.VRFCoordinatorV2PlusMetaData.GetAbi()
+	require.NoError(t, err2)
+	var dataErr rpc.DataError
+	require.True(t, errors.As(err, &dataErr), "call failed without reverting: %v", err)
+	data, ok := dataErr.ErrorData().(string)
+	require.True(t, ok, "unexpected revert data type %T", dataErr.ErrorData())
+	require.Equal(t, hexutil.Encode(coordinatorABI.Errors[errorName].ID.Bytes()[:4]), data, "expected %s revert", errorName)
+}
+
+func mustConfirm(t *testing.T, backend *backends.SimulatedBackend, tx *gethtypes.Transaction) *gethtypes.Receipt {
+	backend.Commit()
+	receipt, err := bind.WaitMined(context.Background(), backend, tx)
+	require.NoError(t, err)
+	require.Equal(t, gethtypes.ReceiptStatusSuccessful, receipt.Status)
+	return receipt
+}
+
+// DrainSubscription spends the LINK balance of subID, which the consumer must be using, by requesting and
+// fulfilling single random words until a fulfillment reverts with InsufficientBalance. Payments vary with the gas
+// and calldata of each fulfillment, so the subscription is drained until the coordinator refuses one rather than
+// until the balance is predicted to fall short. Subscriptions cannot be withdrawn from by their owner, so
+// fulfillments are the only way to drain them short of cancelling them. The payments are asserted to add up to the
+// LINK spent. The remaining balance and the id of the refused request, which stays pending, are returned.
+func DrainSubscription(t *testing.T, c VRFV2PlusContracts, subID *big.Int) (*big.Int, *big.Int) {
+	sub, err := c.Coordinator.GetSubscription(nil, subID)
+	require.NoError(t, err)
+	require.Positive(t, sub.Balance.Sign(), "subscription %s has no LINK to drain", subID)
+	start, paid := sub.Balance, new(big.Int)
+	for {
+		requestID := RequestRandomnessWithConfirmations(t, c, subID, 1)
+		c.Backend.Commit()
+		if _, err = FulfillRandomWords(t, c, requestID); err != nil {
+			assertInsufficientBalance(t, c, subID, requestID, err)
+			return sub.Balance, requestID
+		}
+		paid.Add(paid, FindRandomWordsFulfillment(t, c, requestID).Payment)
+		sub, err = c.Coordinator.GetSubscription(nil, subID)
+		require.NoError(t, err)
+		require.Equal(t, new(big.Int).Sub(start, paid).String(), sub.Balance.String())
+	}
+}
+
+// assertInsufficientBalance asserts that err, returned by the fulfillment of the request with the given id, is an
+// InsufficientBalance revert, and that the request is still pending on an untouched subscription.
+func assertInsufficientBalance(t *testing.T, c VRFV2PlusContracts, subID *big.Int, requestID *big.Int, err error) {
+	before, err2 := c.Coordinator.GetSubscription(nil, subID)
+	require.NoError(t, err2)
+	requireCoordinatorRevert(t, err, "InsufficientBalance")
+
+	c.Backend.Commit()
+	pending, err := c.Coordinator.PendingRequestExists(nil, subID)
+	require.NoError(t, err)
+	require.True(t, pending)
+	commitment, err := c.Coordinator.SRequestCommitments(nil, requestID)
+	require.NoError(t, err)
+	require.NotEqual(t, [32]byte{}, commitment, "request %s is no longer pending", requestID)
+	after, err := c.Coordinator.GetSubscription(nil, subID)
+	require.NoError(t, err)
+	require.Equal(t, before.Balance.String(), after.Balance.String())
+	require.Equal(t, before.EthBalance.String(), after.EthBalance.String())
+}
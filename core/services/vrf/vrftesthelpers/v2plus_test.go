@@ -0,0 +1,115 @@
+package vrftesthelpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+)
+
+func TestVRFV2PlusCallbackGasLimit(t *testing.T) {
+	c := NewVRFV2PlusContracts(t)
+	subID := CreateSubscription(t, c, assets.Ether(10).ToInt())
+	consumer := DeployCallbackGasConsumer(t, c, subID)
+
+	for _, limit := range []uint32{100_000, 2_000_000} {
+		req := RequestCallbackGas(t, c, consumer, subID, limit, 1)
+		c.Backend.Commit()
+		_, err := FulfillRandomWords(t, c, req.RequestId)
+		require.NoError(t, err)
+		AssertCallbackGasRespected(t, c, consumer, req.RequestId, limit)
+	}
+
+	// Storing 50 words needs far more than the tight limit, so the callback runs out of gas before storing any.
+	tight := RequestCallbackGas(t, c, consumer, subID, 100_000, 50)
+	c.Backend.Commit()
+	_, err := FulfillRandomWords(t, c, tight.RequestId)
+	require.NoError(t, err)
+	require.False(t, FindRandomWordsFulfillment(t, c, tight.RequestId).Success)
+	_, err = consumer.SRandomWords(nil, big.NewInt(1))
+	require.Error(t, err, "words of the failed callback were stored")
+
+	generous := RequestCallbackGas(t, c, consumer, subID, 2_000_000, 50)
+	c.Backend.Commit()
+	_, err = FulfillRandomWords(t, c, generous.RequestId)
+	require.NoError(t, err)
+	AssertCallbackGasRespected(t, c, consumer, generous.RequestId, 2_000_000)
+	_, err = consumer.SRandomWords(nil, big.NewInt(49))
+	require.NoError(t, err)
+}
+
+func TestVRFV2PlusCancelSubscription(t *testing.T) {
+	c := NewVRFV2PlusContracts(t)
+	funding := assets.Ether(10).ToInt()
+	subID := CreateSubscription(t, c, funding)
+	recipient := testutils.NewAddress()
+
+	requestID := RequestRandomnessWithConfirmations(t, c, subID, 1)
+	AssertCancelBlockedByPendingRequest(t, c, subID, recipient)
+	receipt, err := FulfillRandomWords(t, c, requestID)
+	require.NoError(t, err)
+	fulfilled, err := c.Coordinator.ParseRandomWordsFulfilled(*receipt.Logs[len(receipt.Logs)-1])
+	require.NoError(t, err)
+	require.True(t, fulfilled.Success)
+	require.Positive(t, fulfilled.Payment.Sign())
+
+	sub, err := c.Coordinator.GetSubscription(nil, subID)
+	require.NoError(t, err)
+	remaining := new(big.Int).Sub(funding, fulfilled.Payment)
+	require.Equal(t, remaining.String(), sub.Balance.String())
+
+	refunded := CancelSubscription(t, c, subID, recipient)
+	require.Equal(t, remaining.String(), refunded.String())
+	balance, err := c.LinkToken.BalanceOf(nil, recipient)
+	require.NoError(t, err)
+	require.Equal(t, remaining.String(), balance.String())
+}
+
+func TestVRFV2PlusOverdraftProtection(t *testing.T) {
+	c := NewVRFV2PlusContracts(t)
+	subID := CreateSubscription(t, c, assets.Ether(1).ToInt())
+	// Measure a fulfillment to fund a second subscription with enough LINK for several of them. Payments vary
+	// between fulfillments, so the subscription is drained until one is refused rather than funded exactly.
+	requestID := RequestRandomnessWithConfirmations(t, c, subID, 1)
+	c.Backend.Commit()
+	_, err := FulfillRandomWords(t, c, requestID)
+	require.NoError(t, err)
+	payment := FindRandomWordsFulfillment(t, c, requestID).Payment
+	CancelSubscription(t, c, subID, testutils.NewAddress())
+
+	subID = CreateSubscription(t, c, new(big.Int).Mul(payment, big.NewInt(5)))
+	remaining, requestID := DrainSubscription(t, c, subID)
+	sub, err := c.Coordinator.GetSubscription(nil, subID)
+	require.NoError(t, err)
+	require.Equal(t, remaining.String(), sub.Balance.String())
+
+	consumerRequest, err := c.Consumer.SRequests(nil, requestID)
+	require.NoError(t, err)
+	require.False(t, consumerRequest.Fulfilled)
+	AssertCancelBlockedByPendingRequest(t, c, subID, testutils.NewAddress())
+}
+
+func TestVRFV2PlusPendingRequestCount(t *testing.T) {
+	c := NewVRFV2PlusContracts(t)
+	subID := CreateSubscription(t, c, assets.Ether(10).ToInt())
+	recipient := testutils.NewAddress()
+	require.Zero(t, PendingRequestCount(t, c, subID))
+
+	first := RequestRandomnessWithConfirmations(t, c, subID, 1)
+	second := RequestRandomnessWithConfirmations(t, c, subID, 1)
+	require.Equal(t, uint64(2), PendingRequestCount(t, c, subID))
+	AssertCancelBlockedByPendingRequest(t, c, subID, recipient)
+
+	_, err := FulfillRandomWords(t, c, first)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), PendingRequestCount(t, c, subID))
+	AssertCancelBlockedByPendingRequest(t, c, subID, recipient)
+
+	_, err = FulfillRandomWords(t, c, second)
+	require.NoError(t, err)
+	require.Zero(t, PendingRequestCount(t, c, subID))
+	CancelSubscription(t, c, subID, recipient)
+}